@@ -0,0 +1,138 @@
+package cryptogo
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/ascii85"
+	"errors"
+	"io"
+)
+
+// Ascii85Encode ascii85 encode (btoa), four zero bytes are written as 'z'
+func Ascii85Encode(src []byte) []byte {
+	dst := make([]byte, ascii85.MaxEncodedLen(len(src)))
+	n := ascii85.Encode(dst, src)
+	return dst[:n]
+}
+
+// Ascii85AdobeEncode ascii85 encode between the <~ and ~> delimiters used
+// by PostScript and PDF
+func Ascii85AdobeEncode(src []byte) []byte {
+	dst := make([]byte, 2, ascii85.MaxEncodedLen(len(src))+4)
+	copy(dst, "<~")
+	dst = append(dst, Ascii85Encode(src)...)
+	return append(dst, "~>"...)
+}
+
+// Ascii85Decode ascii85 decode, with or without the <~ ~> delimiters.
+// Whitespace is ignored and the data ends at ~> if present.
+func Ascii85Decode(src []byte) ([]byte, error) {
+	return io.ReadAll(NewAscii85Decoder(bytes.NewReader(src)))
+}
+
+// NewAscii85Encoder returns a streaming Ascii85Encode writing to w. Close
+// must be called to flush the last partial group.
+func NewAscii85Encoder(w io.Writer) io.WriteCloser {
+	return ascii85.NewEncoder(w)
+}
+
+// NewAscii85AdobeEncoder returns a streaming Ascii85AdobeEncode writing to
+// w. Close must be called to flush the last partial group and write ~>.
+func NewAscii85AdobeEncoder(w io.Writer) io.WriteCloser {
+	return &ascii85AdobeEncoder{w: w, enc: ascii85.NewEncoder(w)}
+}
+
+// NewAscii85Decoder returns a streaming Ascii85Decode reading from r.
+func NewAscii85Decoder(r io.Reader) io.Reader {
+	return ascii85.NewDecoder(&ascii85Delimited{r: bufio.NewReader(r)})
+}
+
+type ascii85AdobeEncoder struct {
+	w       io.Writer
+	enc     io.WriteCloser
+	started bool
+}
+
+func (x *ascii85AdobeEncoder) start() error {
+	if x.started {
+		return nil
+	}
+	x.started = true
+	_, err := io.WriteString(x.w, "<~")
+	return err
+}
+
+func (x *ascii85AdobeEncoder) Write(p []byte) (int, error) {
+	if err := x.start(); err != nil {
+		return 0, err
+	}
+	return x.enc.Write(p)
+}
+
+func (x *ascii85AdobeEncoder) Close() error {
+	if err := x.start(); err != nil {
+		return err
+	}
+	if err := x.enc.Close(); err != nil {
+		return err
+	}
+	_, err := io.WriteString(x.w, "~>")
+	return err
+}
+
+// ascii85Delimited strips an optional leading <~ and ends the input at ~>,
+// which is required once <~ was seen.
+type ascii85Delimited struct {
+	r       *bufio.Reader
+	started bool
+	opened  bool
+	done    bool
+}
+
+var errAscii85Delimiter = errors.New("illegal ascii85 data: malformed ~> delimiter")
+
+func (d *ascii85Delimited) Read(p []byte) (n int, err error) {
+	if d.done {
+		return 0, io.EOF
+	}
+	if !d.started {
+		d.started = true
+		for {
+			c, err := d.r.ReadByte()
+			if err != nil {
+				return 0, err
+			}
+			if !isAscii85Space(c) {
+				d.r.UnreadByte()
+				break
+			}
+		}
+		if prefix, _ := d.r.Peek(2); string(prefix) == "<~" {
+			d.r.Discard(2)
+			d.opened = true
+		}
+	}
+	for n < len(p) {
+		c, err := d.r.ReadByte()
+		if err == io.EOF && d.opened {
+			return n, errAscii85Delimiter
+		}
+		if err != nil {
+			return n, err
+		}
+		if c == '~' {
+			if next, err := d.r.ReadByte(); err != nil || next != '>' {
+				return n, errAscii85Delimiter
+			}
+			d.done = true
+			return n, io.EOF
+		}
+		p[n] = c
+		n++
+	}
+	return n, nil
+}
+
+func isAscii85Space(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' || c == 0
+}
@@ -0,0 +1,112 @@
+package cryptogo
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"testing"
+	"testing/iotest"
+)
+
+func ExampleAscii85Encode() {
+	encode := Ascii85Encode([]byte("TrumanWong"))
+	fmt.Println(string(encode))
+	// Output: <-!+u@;].bDJ&
+}
+
+func ExampleAscii85AdobeEncode() {
+	encode := Ascii85AdobeEncode([]byte("TrumanWong"))
+	fmt.Println(string(encode))
+	// Output: <~<-!+u@;].bDJ&~>
+}
+
+func TestAscii85Encode(t *testing.T) {
+	tests := []struct {
+		src      string
+		expected string
+	}{
+		{"", ""},
+		{"\x00\x00\x00\x00", "z"},
+		{"\x00\x00\x00\x00a", "z@/"},
+		{"\x00\x00\x00", "!!!!"},
+		{"hello world!", "BOu!rD]j7BEbo80"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			assert.Equal(t, tt.expected, string(Ascii85Encode([]byte(tt.src))))
+			assert.Equal(t, "<~"+tt.expected+"~>", string(Ascii85AdobeEncode([]byte(tt.src))))
+		})
+	}
+}
+
+func ExampleAscii85Decode() {
+	decode, err := Ascii85Decode([]byte("<~<-!+u@;].bDJ&~>"))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(string(decode))
+	// Output: TrumanWong
+}
+
+func TestAscii85Decode(t *testing.T) {
+	t.Run("TestAscii85Decode", func(t *testing.T) {
+		for _, src := range []string{
+			"<-!+u@;].bDJ&",
+			"<~<-!+u@;].bDJ&~>",
+			"  <~<-!+u@\n;].bDJ&~>\n",
+			// PDF streams only have the end of data marker
+			"<-!+u@;].bDJ&~>",
+			// anything after ~> is ignored
+			"<~<-!+u@;].bDJ&~>trailer",
+		} {
+			ret, err := Ascii85Decode([]byte(src))
+			assert.NoError(t, err, src)
+			assert.Equal(t, []byte("TrumanWong"), ret, src)
+		}
+
+		ret, err := Ascii85Decode([]byte("<~z@/~>"))
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("\x00\x00\x00\x00a"), ret)
+		ret, err = Ascii85Decode([]byte("<~~>"))
+		assert.NoError(t, err)
+		assert.Empty(t, ret)
+	})
+
+	t.Run("TestAscii85DecodeInvalid", func(t *testing.T) {
+		for _, src := range []string{"<~<-!+u@;].bDJ&", "<-!+u@;].bDJ&~", "<-!+u@;].bDJ&~x", "<-!+v@", "<-z!+"} {
+			_, err := Ascii85Decode([]byte(src))
+			assert.Error(t, err, src)
+		}
+	})
+}
+
+func TestAscii85Stream(t *testing.T) {
+	src := bytes.Repeat([]byte("TrumanWong\x00\x00\x00\x00"), 500)
+
+	var buf bytes.Buffer
+	w := NewAscii85AdobeEncoder(&buf)
+	for i := 0; i < len(src); i += 7 {
+		_, err := w.Write(src[i:min(i+7, len(src))])
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, w.Close())
+	assert.Equal(t, Ascii85AdobeEncode(src), buf.Bytes())
+
+	ret, err := io.ReadAll(NewAscii85Decoder(iotest.OneByteReader(&buf)))
+	assert.NoError(t, err)
+	assert.Equal(t, src, ret)
+
+	buf.Reset()
+	w = NewAscii85Encoder(&buf)
+	_, err = w.Write(src)
+	assert.NoError(t, err)
+	assert.NoError(t, w.Close())
+	assert.Equal(t, Ascii85Encode(src), buf.Bytes())
+
+	// the empty stream still has its delimiters
+	buf.Reset()
+	assert.NoError(t, NewAscii85AdobeEncoder(&buf).Close())
+	assert.Equal(t, "<~~>", buf.String())
+}
@@ -1,6 +1,11 @@
 package cryptogo
 
-import "encoding/base32"
+import (
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"io"
+)
 
 func Base32Encode(src []byte) []byte {
 	dst := make([]byte, base32.StdEncoding.EncodedLen(len(src)))
@@ -16,3 +21,292 @@ func Base32Decode(src []byte) ([]byte, error) {
 	}
 	return dst[:n], nil
 }
+
+type base32Alphabet string
+
+const (
+	// Base32Std is the standard alphabet of RFC 4648 section 6.
+	Base32Std base32Alphabet = "std"
+	// Base32Hex is the "extended hex" alphabet of RFC 4648 section 7, which
+	// keeps the sort order of the data.
+	Base32Hex base32Alphabet = "hex"
+	// Base32Crockford is Douglas Crockford's alphabet without I, L, O and
+	// U. It is never padded, and decoding ignores case and hyphens and
+	// reads I and L as 1 and O as 0.
+	Base32Crockford base32Alphabet = "crockford"
+)
+
+const crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// crockfordCheck are the symbols of the values 0 to 36 of the check symbol.
+const crockfordCheck = crockfordAlphabet + "*~$=U"
+
+var crockfordEncoding = base32.NewEncoding(crockfordAlphabet).WithPadding(base32.NoPadding)
+
+// ErrBase32CheckSymbol is returned when decoding Base32Crockford with Check
+// and the check symbol does not match.
+var ErrBase32CheckSymbol = errors.New("base32: invalid check symbol")
+
+// Base32Options selects the variant of Base32EncodeWithOptions,
+// Base32DecodeWithOptions, NewBase32Encoder and NewBase32Decoder. Nil
+// options mean the padded Base32Std of Base32Encode.
+type Base32Options struct {
+	Alphabet base32Alphabet
+	// Raw omits the = padding.
+	Raw bool
+	// Check appends a Crockford check symbol, the value of the encoded
+	// symbols modulo 37. It is only used with Base32Crockford.
+	Check bool
+	// LineLength breaks the encoded output with CRLF every LineLength
+	// characters.
+	LineLength int
+	// Lenient ignores all whitespace when decoding, not only the CR and LF
+	// always skipped.
+	Lenient bool
+}
+
+func (opts *Base32Options) encoding() (*base32.Encoding, error) {
+	if opts == nil {
+		return base32.StdEncoding, nil
+	}
+	var enc *base32.Encoding
+	switch opts.Alphabet {
+	case Base32Std, "":
+		enc = base32.StdEncoding
+	case Base32Hex:
+		enc = base32.HexEncoding
+	case Base32Crockford:
+		return crockfordEncoding, nil
+	default:
+		return nil, fmt.Errorf("%w: base32 %q", ErrUnsupportedAlphabet, opts.Alphabet)
+	}
+	if opts.Raw {
+		enc = enc.WithPadding(base32.NoPadding)
+	}
+	return enc, nil
+}
+
+func (opts *Base32Options) crockford() bool {
+	return opts != nil && opts.Alphabet == Base32Crockford
+}
+
+// mapping returns how the input is normalized before decoding, nil if it
+// is not.
+func (opts *Base32Options) mapping() func(c byte) (byte, bool) {
+	switch {
+	case opts.crockford():
+		lenient := opts.Lenient
+		return func(c byte) (byte, bool) {
+			switch c {
+			case '-', '\r', '\n':
+				return c, false
+			case 'I', 'i', 'L', 'l':
+				return '1', true
+			case 'O', 'o':
+				return '0', true
+			}
+			if 'a' <= c && c <= 'z' {
+				return c - 'a' + 'A', true
+			}
+			if lenient {
+				return dropSpace(c)
+			}
+			return c, true
+		}
+	case opts != nil && opts.Lenient:
+		return dropSpace
+	}
+	return nil
+}
+
+// Base32EncodeWithOptions base32 encode with the variant in opts.
+func Base32EncodeWithOptions(src []byte, opts *Base32Options) ([]byte, error) {
+	enc, err := opts.encoding()
+	if err != nil {
+		return nil, err
+	}
+	dst := make([]byte, enc.EncodedLen(len(src)), enc.EncodedLen(len(src))+1)
+	enc.Encode(dst, src)
+	if opts == nil {
+		return dst, nil
+	}
+	if opts.crockford() && opts.Check {
+		var sum crockfordSum
+		sum.write(dst)
+		dst = append(dst, sum.symbol())
+	}
+	return wrapLines(dst, opts.LineLength), nil
+}
+
+// Base32DecodeWithOptions base32 decode with the variant in opts.
+func Base32DecodeWithOptions(src []byte, opts *Base32Options) ([]byte, error) {
+	enc, err := opts.encoding()
+	if err != nil {
+		return nil, err
+	}
+	if mapping := opts.mapping(); mapping != nil {
+		src = mapBytes(src, mapping)
+	}
+	if opts.crockford() && opts.Check {
+		if len(src) == 0 {
+			return nil, ErrBase32CheckSymbol
+		}
+		var sum crockfordSum
+		sum.write(src[:len(src)-1])
+		if sum.symbol() != src[len(src)-1] {
+			return nil, ErrBase32CheckSymbol
+		}
+		src = src[:len(src)-1]
+	}
+	dst := make([]byte, enc.DecodedLen(len(src)))
+	n, err := enc.Decode(dst, src)
+	if err != nil {
+		return nil, err
+	}
+	return dst[:n], nil
+}
+
+// NewBase32Encoder returns a streaming Base32EncodeWithOptions writing to w.
+// Close must be called to flush the last partial block and the check
+// symbol. An unsupported Alphabet is returned by every call.
+func NewBase32Encoder(w io.Writer, opts *Base32Options) io.WriteCloser {
+	enc, err := opts.encoding()
+	if err != nil {
+		return errStream{err}
+	}
+	if opts != nil && opts.LineLength > 0 {
+		w = &lineWriter{w: w, n: opts.LineLength}
+	}
+	if opts.crockford() && opts.Check {
+		cw := &crockfordCheckWriter{w: w}
+		return &crockfordCheckEncoder{WriteCloser: base32.NewEncoder(crockfordEncoding, cw), cw: cw}
+	}
+	return base32.NewEncoder(enc, w)
+}
+
+// NewBase32Decoder returns a streaming Base32DecodeWithOptions reading
+// from r. An unsupported Alphabet is returned by Read.
+func NewBase32Decoder(r io.Reader, opts *Base32Options) io.Reader {
+	enc, err := opts.encoding()
+	if err != nil {
+		return errStream{err}
+	}
+	if mapping := opts.mapping(); mapping != nil {
+		r = &mapReader{r: r, mapping: mapping}
+	}
+	if opts.crockford() && opts.Check {
+		r = &crockfordCheckReader{r: r}
+	}
+	if opts != nil && (opts.Raw || opts.crockford()) {
+		r = &blockReader{r: r, size: 8}
+	}
+	return base32.NewDecoder(enc, r)
+}
+
+// blockReader returns multiples of size bytes until the end of r. The
+// unpadded decoders of encoding/base32 take a short read for the end of the
+// input.
+type blockReader struct {
+	r    io.Reader
+	size int
+	err  error
+}
+
+func (b *blockReader) Read(p []byte) (n int, err error) {
+	if len(p) >= b.size {
+		p = p[:len(p)/b.size*b.size]
+	}
+	for n == 0 || n%b.size != 0 && n < len(p) {
+		if b.err != nil {
+			return n, b.err
+		}
+		var nn int
+		nn, b.err = b.r.Read(p[n:])
+		n += nn
+	}
+	return n, nil
+}
+
+// crockfordSum computes the check symbol of a Crockford string.
+type crockfordSum uint8
+
+func (s *crockfordSum) write(p []byte) {
+	for _, c := range p {
+		// unknown symbols are left to the decoder to report
+		v := crockfordValue(c)
+		*s = crockfordSum((uint(*s)*32 + uint(v)) % 37)
+	}
+}
+
+func (s crockfordSum) symbol() byte {
+	return crockfordCheck[s]
+}
+
+func crockfordValue(c byte) byte {
+	for i := 0; i < len(crockfordAlphabet); i++ {
+		if crockfordAlphabet[i] == c {
+			return byte(i)
+		}
+	}
+	return 0
+}
+
+// crockfordCheckWriter sums the symbols written through it.
+type crockfordCheckWriter struct {
+	w   io.Writer
+	sum crockfordSum
+}
+
+func (c *crockfordCheckWriter) Write(p []byte) (int, error) {
+	c.sum.write(p)
+	return c.w.Write(p)
+}
+
+// crockfordCheckEncoder writes the check symbol on Close.
+type crockfordCheckEncoder struct {
+	io.WriteCloser
+	cw *crockfordCheckWriter
+}
+
+func (e *crockfordCheckEncoder) Close() error {
+	if err := e.WriteCloser.Close(); err != nil {
+		return err
+	}
+	_, err := e.cw.w.Write([]byte{e.cw.sum.symbol()})
+	return err
+}
+
+// crockfordCheckReader holds back the last symbol of r and checks it at
+// the end of the input.
+type crockfordCheckReader struct {
+	r       io.Reader
+	sum     crockfordSum
+	err     error
+	checked bool
+	buf     [1024]byte
+	nbuf    int
+}
+
+func (c *crockfordCheckReader) Read(p []byte) (int, error) {
+	for {
+		if c.nbuf > 1 {
+			n := copy(p, c.buf[:c.nbuf-1])
+			c.sum.write(p[:n])
+			c.nbuf = copy(c.buf[:], c.buf[n:c.nbuf])
+			return n, nil
+		}
+		if c.err == io.EOF && !c.checked {
+			c.checked = true
+			if c.nbuf != 1 || c.buf[0] != c.sum.symbol() {
+				c.err = ErrBase32CheckSymbol
+			}
+			c.nbuf = 0
+		}
+		if c.err != nil {
+			return 0, c.err
+		}
+		var n int
+		n, c.err = c.r.Read(c.buf[c.nbuf:])
+		c.nbuf += n
+	}
+}
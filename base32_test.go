@@ -1,9 +1,13 @@
 package cryptogo
 
 import (
+	"bytes"
 	"fmt"
 	"github.com/stretchr/testify/assert"
+	"io"
+	"strings"
 	"testing"
+	"testing/iotest"
 )
 
 func ExampleBase32Encode() {
@@ -35,3 +39,140 @@ func TestBase32Decode(t *testing.T) {
 		assert.Equal(t, []byte("TrumanWong"), ret)
 	})
 }
+
+func ExampleBase32EncodeWithOptions() {
+	encode, err := Base32EncodeWithOptions([]byte("TrumanWong"), &Base32Options{Alphabet: Base32Crockford, Check: true})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(string(encode))
+	// Output: AHS7AVB1DSBPYVK7C
+}
+
+func TestBase32EncodeWithOptions(t *testing.T) {
+	src := []byte("hello world")
+	tests := []struct {
+		name     string
+		opts     *Base32Options
+		expected string
+	}{
+		{"nil", nil, "NBSWY3DPEB3W64TMMQ======"},
+		{"raw", &Base32Options{Raw: true}, "NBSWY3DPEB3W64TMMQ"},
+		{"hex", &Base32Options{Alphabet: Base32Hex}, "D1IMOR3F41RMUSJCCG======"},
+		{"hex raw", &Base32Options{Alphabet: Base32Hex, Raw: true}, "D1IMOR3F41RMUSJCCG"},
+		{"crockford", &Base32Options{Alphabet: Base32Crockford}, "D1JPRV3F41VPYWKCCG"},
+		{"crockford check", &Base32Options{Alphabet: Base32Crockford, Check: true}, "D1JPRV3F41VPYWKCCGT"},
+		{"check without crockford", &Base32Options{Check: true}, "NBSWY3DPEB3W64TMMQ======"},
+		{"wrap", &Base32Options{Alphabet: Base32Crockford, Check: true, LineLength: 8}, "D1JPRV3F\r\n41VPYWKC\r\nCGT"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encode, err := Base32EncodeWithOptions(src, tt.opts)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, string(encode))
+		})
+	}
+
+	encode, err := Base32EncodeWithOptions([]byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, &Base32Options{Alphabet: Base32Crockford, Check: true})
+	assert.NoError(t, err)
+	assert.Equal(t, "000G40R40M30E209Y", string(encode))
+	encode, err = Base32EncodeWithOptions(nil, &Base32Options{Alphabet: Base32Crockford, Check: true})
+	assert.NoError(t, err)
+	assert.Equal(t, "0", string(encode))
+
+	_, err = Base32EncodeWithOptions(src, &Base32Options{Alphabet: "z-base-32"})
+	assert.ErrorIs(t, err, ErrUnsupportedAlphabet)
+}
+
+func TestBase32DecodeWithOptions(t *testing.T) {
+	t.Run("TestBase32DecodeWithOptions", func(t *testing.T) {
+		ret, err := Base32DecodeWithOptions([]byte("D1IMOR3F41RMUSJCCG======"), &Base32Options{Alphabet: Base32Hex})
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("hello world"), ret)
+
+		ret, err = Base32DecodeWithOptions([]byte("NBSWY3DPEB3W64TMMQ"), &Base32Options{Raw: true})
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("hello world"), ret)
+
+		ret, err = Base32DecodeWithOptions([]byte(" NBSWY3DP\tEB3W64TM\r\nMQ====== "), &Base32Options{Lenient: true})
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("hello world"), ret)
+
+		// case, hyphens and the confusable I, L and O
+		for _, src := range []string{"D1JPRV3F41VPYWKCCG", "d1jprv3f41vpywkccg", "Dijprv3f4L-vpywkccg", "D1JP-RV3F-41VP-YWKC-CG\n"} {
+			ret, err = Base32DecodeWithOptions([]byte(src), &Base32Options{Alphabet: Base32Crockford})
+			assert.NoError(t, err, src)
+			assert.Equal(t, []byte("hello world"), ret, src)
+		}
+		ret, err = Base32DecodeWithOptions([]byte("OOO-g40r-4Om3-Oe2O-9y"), &Base32Options{Alphabet: Base32Crockford, Check: true})
+		assert.NoError(t, err)
+		assert.Equal(t, []byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, ret)
+		ret, err = Base32DecodeWithOptions([]byte("D1JPRV3F\r\n41VPYWKC\r\nCGT\r\n"), &Base32Options{Alphabet: Base32Crockford, Check: true})
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("hello world"), ret)
+	})
+
+	t.Run("TestBase32DecodeWithOptionsInvalid", func(t *testing.T) {
+		crockford := &Base32Options{Alphabet: Base32Crockford, Check: true}
+		for _, src := range []string{"D1JPRV3F41VPYWKCCG", "D1JPRV3F41VPYWKCCGS", "D1JPRV3F41VPYWKCDGT", ""} {
+			_, err := Base32DecodeWithOptions([]byte(src), crockford)
+			assert.ErrorIs(t, err, ErrBase32CheckSymbol, src)
+		}
+		_, err := Base32DecodeWithOptions([]byte("D1JPRV3F41VPYWKCCU"), &Base32Options{Alphabet: Base32Crockford})
+		assert.Error(t, err)
+		_, err = Base32DecodeWithOptions([]byte("NBSWY3DP EB3W64TMMQ======"), nil)
+		assert.Error(t, err)
+		_, err = Base32DecodeWithOptions([]byte("D1JPRV3F 41VPYWKCCG"), &Base32Options{Alphabet: Base32Crockford})
+		assert.Error(t, err)
+		_, err = Base32DecodeWithOptions([]byte("NBSWY3DPEB3W64TMMQ"), &Base32Options{Alphabet: "z-base-32"})
+		assert.ErrorIs(t, err, ErrUnsupportedAlphabet)
+	})
+}
+
+func TestBase32Stream(t *testing.T) {
+	src := make([]byte, 1001)
+	for i := range src {
+		src[i] = byte(i * 7)
+	}
+	for _, opts := range []*Base32Options{
+		nil,
+		{Raw: true},
+		{Alphabet: Base32Hex, LineLength: 76},
+		{Alphabet: Base32Crockford},
+		{Alphabet: Base32Crockford, Check: true},
+		{Alphabet: Base32Crockford, Check: true, LineLength: 10, Lenient: true},
+	} {
+		var buf bytes.Buffer
+		w := NewBase32Encoder(&buf, opts)
+		for i := 0; i < len(src); i += 13 {
+			_, err := w.Write(src[i:min(i+13, len(src))])
+			assert.NoError(t, err)
+		}
+		assert.NoError(t, w.Close())
+		encode, err := Base32EncodeWithOptions(src, opts)
+		assert.NoError(t, err)
+		assert.Equal(t, encode, buf.Bytes())
+
+		ret, err := io.ReadAll(NewBase32Decoder(iotest.OneByteReader(&buf), opts))
+		assert.NoError(t, err)
+		assert.Equal(t, src, ret)
+	}
+
+	crockford := &Base32Options{Alphabet: Base32Crockford, Check: true, Lenient: true}
+	ret, err := io.ReadAll(NewBase32Decoder(strings.NewReader("d1jp-rv3f 41vp-ywkc cgt\n"), crockford))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("hello world"), ret)
+	_, err = io.ReadAll(NewBase32Decoder(strings.NewReader("D1JPRV3F41VPYWKCCGS"), crockford))
+	assert.ErrorIs(t, err, ErrBase32CheckSymbol)
+	_, err = io.ReadAll(NewBase32Decoder(strings.NewReader(""), crockford))
+	assert.ErrorIs(t, err, ErrBase32CheckSymbol)
+
+	invalid := &Base32Options{Alphabet: "z-base-32"}
+	w := NewBase32Encoder(io.Discard, invalid)
+	_, err = w.Write(src)
+	assert.ErrorIs(t, err, ErrUnsupportedAlphabet)
+	assert.ErrorIs(t, w.Close(), ErrUnsupportedAlphabet)
+	_, err = io.ReadAll(NewBase32Decoder(strings.NewReader("NBSWY3DPEB3W64TMMQ"), invalid))
+	assert.ErrorIs(t, err, ErrUnsupportedAlphabet)
+}
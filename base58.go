@@ -0,0 +1,67 @@
+package cryptogo
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+)
+
+var (
+	base58Bitcoin = newRadixEncoding("base58", "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz")
+	base58Flickr  = newRadixEncoding("base58", "123456789abcdefghijkmnopqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ")
+)
+
+// ErrBase58Checksum is returned by Base58CheckDecode when the checksum does
+// not match.
+var ErrBase58Checksum = errors.New("base58check: invalid checksum")
+
+// Base58Encode base58 encode with the Bitcoin alphabet
+func Base58Encode(src []byte) []byte {
+	return base58Bitcoin.Encode(src)
+}
+
+// Base58Decode base58 decode with the Bitcoin alphabet
+func Base58Decode(src []byte) ([]byte, error) {
+	return base58Bitcoin.Decode(src)
+}
+
+// Base58FlickrEncode base58 encode with the Flickr alphabet
+func Base58FlickrEncode(src []byte) []byte {
+	return base58Flickr.Encode(src)
+}
+
+// Base58FlickrDecode base58 decode with the Flickr alphabet
+func Base58FlickrDecode(src []byte) ([]byte, error) {
+	return base58Flickr.Decode(src)
+}
+
+// Base58CheckEncode base58 encode src followed by the first 4 bytes of its
+// double SHA-256. src usually starts with a version byte, e.g. 0x00 for a
+// Bitcoin address.
+func Base58CheckEncode(src []byte) []byte {
+	return base58Bitcoin.Encode(append(src[:len(src):len(src)], base58Checksum(src)...))
+}
+
+// Base58CheckDecode base58 decode and verify the checksum, return the data
+// without it.
+func Base58CheckDecode(src []byte) ([]byte, error) {
+	dst, err := base58Bitcoin.Decode(src)
+	if err != nil {
+		return nil, err
+	}
+	if len(dst) < 4 {
+		return nil, fmt.Errorf("%w: data is too short", ErrBase58Checksum)
+	}
+	data, checksum := dst[:len(dst)-4], dst[len(dst)-4:]
+	if !bytes.Equal(checksum, base58Checksum(data)) {
+		return nil, ErrBase58Checksum
+	}
+	return data, nil
+}
+
+func base58Checksum(src []byte) []byte {
+	h := sha256.Sum256(src)
+	h = sha256.Sum256(h[:])
+	return h[:4]
+}
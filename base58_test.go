@@ -0,0 +1,113 @@
+package cryptogo
+
+import (
+	"encoding/hex"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func ExampleBase58Encode() {
+	encode := Base58Encode([]byte("TrumanWong"))
+	fmt.Println(string(encode))
+	// Output: 5kB8kDtVDiaWop
+}
+
+func TestBase58Encode(t *testing.T) {
+	tests := []struct {
+		src      string
+		expected string
+		flickr   string
+	}{
+		{"", "", ""},
+		{"\x00", "1", "1"},
+		{"\x00\x00", "11", "11"},
+		{"\x00\x00\x28\x7f\xb4\xcd", "11233QC4", "11233pc4"},
+		{"TrumanWong", "5kB8kDtVDiaWop", "5Kb8KdTudHzvNP"},
+		{"Hello World!", "2NEpo7TZRRrLZSi2U", "2nePN7syqqRkyrH2t"},
+		{"The quick brown fox jumps over the lazy dog.", "USm3fpXnKG5EUBx2ndxBDMPVciP5hGey2Jh4NDv6gmeo1LkMeiKrLJUUBk6Z", "trL3EPwMjg5etbX2MCXbdmouBHo5GgDY2iG4ndV6FLDN1kKmDHjRkittbK6y"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			assert.Equal(t, tt.expected, string(Base58Encode([]byte(tt.src))))
+			assert.Equal(t, tt.flickr, string(Base58FlickrEncode([]byte(tt.src))))
+		})
+	}
+}
+
+func ExampleBase58Decode() {
+	decode, err := Base58Decode([]byte("5kB8kDtVDiaWop"))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(string(decode))
+	// Output: TrumanWong
+}
+
+func TestBase58Decode(t *testing.T) {
+	t.Run("TestBase58Decode", func(t *testing.T) {
+		ret, err := Base58Decode([]byte("11233QC4"))
+		assert.NoError(t, err)
+		assert.Equal(t, []byte{0, 0, 0x28, 0x7f, 0xb4, 0xcd}, ret)
+
+		ret, err = Base58Decode([]byte(""))
+		assert.NoError(t, err)
+		assert.Empty(t, ret)
+	})
+
+	t.Run("TestBase58FlickrDecode", func(t *testing.T) {
+		ret, err := Base58FlickrDecode([]byte("5Kb8KdTudHzvNP"))
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("TrumanWong"), ret)
+	})
+
+	t.Run("TestBase58DecodeInvalid", func(t *testing.T) {
+		for _, src := range []string{"0", "O", "I", "l", "5kB8k+tVDiaWop", "5kB8kDtVDiaWop\n"} {
+			_, err := Base58Decode([]byte(src))
+			assert.Error(t, err, src)
+		}
+	})
+}
+
+func ExampleBase58CheckEncode() {
+	// version byte 0x00 followed by a public key hash
+	src, _ := hex.DecodeString("00f54a5851e9372b87810a8e60cdd2e7cfd80b6e31")
+	fmt.Println(string(Base58CheckEncode(src)))
+	// Output: 1PMycacnJaSqwwJqjawXBErnLsZ7RkXUAs
+}
+
+func TestBase58CheckEncode(t *testing.T) {
+	t.Run("TestBase58CheckEncode", func(t *testing.T) {
+		assert.Equal(t, []byte("1111111111111111111114oLvT2"), Base58CheckEncode(make([]byte, 21)))
+		assert.Equal(t, []byte("3QJmnh"), Base58CheckEncode(nil))
+
+		// src is not modified
+		src := make([]byte, 1, 8)
+		Base58CheckEncode(src)
+		assert.Equal(t, make([]byte, 4), src[1:5])
+	})
+}
+
+func TestBase58CheckDecode(t *testing.T) {
+	t.Run("TestBase58CheckDecode", func(t *testing.T) {
+		ret, err := Base58CheckDecode([]byte("1PMycacnJaSqwwJqjawXBErnLsZ7RkXUAs"))
+		assert.NoError(t, err)
+		assert.Equal(t, "00f54a5851e9372b87810a8e60cdd2e7cfd80b6e31", hex.EncodeToString(ret))
+
+		ret, err = Base58CheckDecode([]byte("3QJmnh"))
+		assert.NoError(t, err)
+		assert.Empty(t, ret)
+	})
+
+	t.Run("TestBase58CheckDecodeInvalid", func(t *testing.T) {
+		_, err := Base58CheckDecode([]byte("1PMycacnJaSqwwJqjawXBErnLsZ7RkXUAt"))
+		assert.ErrorIs(t, err, ErrBase58Checksum)
+		_, err = Base58CheckDecode([]byte("1PMycacnJaSqwwJqjawXBErnLsZ7RkXUA"))
+		assert.ErrorIs(t, err, ErrBase58Checksum)
+		_, err = Base58CheckDecode([]byte("111"))
+		assert.ErrorIs(t, err, ErrBase58Checksum)
+		_, err = Base58CheckDecode([]byte("1PMycacnJaSqwwJqjawXBErnLsZ7RkXU0s"))
+		assert.Error(t, err)
+	})
+}
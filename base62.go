@@ -0,0 +1,15 @@
+package cryptogo
+
+var base62 = newRadixEncoding("base62", "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz")
+
+// Base62Encode base62 encode with the 0-9A-Za-z alphabet, the output only
+// contains letters and digits, e.g. for short URL-safe IDs. Leading zero
+// bytes are kept as leading '0' characters, as in base58.
+func Base62Encode(src []byte) []byte {
+	return base62.Encode(src)
+}
+
+// Base62Decode base62 decode with the 0-9A-Za-z alphabet
+func Base62Decode(src []byte) ([]byte, error) {
+	return base62.Decode(src)
+}
@@ -0,0 +1,55 @@
+package cryptogo
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func ExampleBase62Encode() {
+	encode := Base62Encode([]byte("TrumanWong"))
+	fmt.Println(string(encode))
+	// Output: 1zbeWNxAiifwpj
+}
+
+func TestBase62Encode(t *testing.T) {
+	tests := []struct {
+		src      string
+		expected string
+	}{
+		{"", ""},
+		{"\x00", "0"},
+		{"\x00\x00\x28\x7f\xb4\xcd", "00jyw3x"},
+		{"TrumanWong", "1zbeWNxAiifwpj"},
+		{"Hello World!", "T8dgcjRGkZ3aysdN"},
+		{"The quick brown fox jumps over the lazy dog.", "XGPLPeg6g0VCuZCSfg8LKEZOBVFQ79VvzR8f2OlDiCg5SwBJDmeq6nysKtS"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			assert.Equal(t, tt.expected, string(Base62Encode([]byte(tt.src))))
+		})
+	}
+}
+
+func ExampleBase62Decode() {
+	decode, err := Base62Decode([]byte("1zbeWNxAiifwpj"))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(string(decode))
+	// Output: TrumanWong
+}
+
+func TestBase62Decode(t *testing.T) {
+	t.Run("TestBase62Decode", func(t *testing.T) {
+		ret, err := Base62Decode([]byte("00jyw3x"))
+		assert.NoError(t, err)
+		assert.Equal(t, []byte{0, 0, 0x28, 0x7f, 0xb4, 0xcd}, ret)
+
+		for _, src := range []string{"-", "_", "1zbeWNxA=ifwpj"} {
+			_, err = Base62Decode([]byte(src))
+			assert.Error(t, err, src)
+		}
+	})
+}
@@ -2,8 +2,59 @@ package cryptogo
 
 import (
 	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
 )
 
+type base64Alphabet string
+
+const (
+	// Base64Std is the standard alphabet of RFC 4648 section 4.
+	Base64Std base64Alphabet = "std"
+	// Base64URL is the URL and filename safe alphabet of RFC 4648 section
+	// 5, used by JWT.
+	Base64URL base64Alphabet = "url"
+)
+
+// ErrUnsupportedAlphabet is returned for an unknown Base64Options or
+// Base32Options Alphabet.
+var ErrUnsupportedAlphabet = errors.New("cryptogo: unsupported alphabet")
+
+// Base64Options selects the variant of Base64EncodeWithOptions,
+// Base64DecodeWithOptions, NewBase64Encoder and NewBase64Decoder. Nil
+// options mean the padded Base64Std of Base64Encode.
+type Base64Options struct {
+	Alphabet base64Alphabet
+	// Raw omits the = padding.
+	Raw bool
+	// LineLength breaks the encoded output with CRLF every LineLength
+	// characters, 76 for MIME (RFC 2045).
+	LineLength int
+	// Lenient ignores all whitespace when decoding, not only the CR and LF
+	// always skipped.
+	Lenient bool
+}
+
+func (opts *Base64Options) encoding() (*base64.Encoding, error) {
+	if opts == nil {
+		return base64.StdEncoding, nil
+	}
+	var enc *base64.Encoding
+	switch opts.Alphabet {
+	case Base64Std, "":
+		enc = base64.StdEncoding
+	case Base64URL:
+		enc = base64.URLEncoding
+	default:
+		return nil, fmt.Errorf("%w: base64 %q", ErrUnsupportedAlphabet, opts.Alphabet)
+	}
+	if opts.Raw {
+		enc = enc.WithPadding(base64.NoPadding)
+	}
+	return enc, nil
+}
+
 // Base64Encode base64 encode
 func Base64Encode(src []byte) []byte {
 	dst := make([]byte, base64.StdEncoding.EncodedLen(len(src)))
@@ -20,3 +71,61 @@ func Base64Decode(src []byte) ([]byte, error) {
 	}
 	return dst[:n], nil
 }
+
+// Base64EncodeWithOptions base64 encode with the variant in opts.
+func Base64EncodeWithOptions(src []byte, opts *Base64Options) ([]byte, error) {
+	enc, err := opts.encoding()
+	if err != nil {
+		return nil, err
+	}
+	dst := make([]byte, enc.EncodedLen(len(src)))
+	enc.Encode(dst, src)
+	if opts != nil {
+		dst = wrapLines(dst, opts.LineLength)
+	}
+	return dst, nil
+}
+
+// Base64DecodeWithOptions base64 decode with the variant in opts.
+func Base64DecodeWithOptions(src []byte, opts *Base64Options) ([]byte, error) {
+	enc, err := opts.encoding()
+	if err != nil {
+		return nil, err
+	}
+	if opts != nil && opts.Lenient {
+		src = mapBytes(src, dropSpace)
+	}
+	dst := make([]byte, enc.DecodedLen(len(src)))
+	n, err := enc.Decode(dst, src)
+	if err != nil {
+		return nil, err
+	}
+	return dst[:n], nil
+}
+
+// NewBase64Encoder returns a streaming Base64EncodeWithOptions writing to w.
+// Close must be called to flush the last partial block. An unsupported
+// Alphabet is returned by every call.
+func NewBase64Encoder(w io.Writer, opts *Base64Options) io.WriteCloser {
+	enc, err := opts.encoding()
+	if err != nil {
+		return errStream{err}
+	}
+	if opts != nil && opts.LineLength > 0 {
+		w = &lineWriter{w: w, n: opts.LineLength}
+	}
+	return base64.NewEncoder(enc, w)
+}
+
+// NewBase64Decoder returns a streaming Base64DecodeWithOptions reading
+// from r. An unsupported Alphabet is returned by Read.
+func NewBase64Decoder(r io.Reader, opts *Base64Options) io.Reader {
+	enc, err := opts.encoding()
+	if err != nil {
+		return errStream{err}
+	}
+	if opts != nil && opts.Lenient {
+		r = &mapReader{r: r, mapping: dropSpace}
+	}
+	return base64.NewDecoder(enc, r)
+}
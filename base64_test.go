@@ -1,9 +1,13 @@
 package cryptogo
 
 import (
+	"bytes"
 	"fmt"
 	"github.com/stretchr/testify/assert"
+	"io"
+	"strings"
 	"testing"
+	"testing/iotest"
 )
 
 func ExampleBase64Encode() {
@@ -35,3 +39,121 @@ func TestBase64Decode(t *testing.T) {
 		assert.Equal(t, []byte("TrumanWong"), ret)
 	})
 }
+
+func ExampleBase64EncodeWithOptions() {
+	// JWT style base64url without padding
+	encode, err := Base64EncodeWithOptions([]byte("TrumanWong?>"), &Base64Options{Alphabet: Base64URL, Raw: true})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(string(encode))
+	// Output: VHJ1bWFuV29uZz8-
+}
+
+func TestBase64EncodeWithOptions(t *testing.T) {
+	src := []byte("TrumanWong\xfb\xff")
+	tests := []struct {
+		name     string
+		opts     *Base64Options
+		expected string
+	}{
+		{"nil", nil, "VHJ1bWFuV29uZ/v/"},
+		{"std", &Base64Options{}, "VHJ1bWFuV29uZ/v/"},
+		{"url", &Base64Options{Alphabet: Base64URL}, "VHJ1bWFuV29uZ_v_"},
+		{"raw", &Base64Options{Raw: true}, "VHJ1bWFuV29uZ/v/"},
+		{"wrap", &Base64Options{LineLength: 5}, "VHJ1b\r\nWFuV2\r\n9uZ/v\r\n/"},
+		{"wrap exact", &Base64Options{LineLength: 8}, "VHJ1bWFu\r\nV29uZ/v/"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encode, err := Base64EncodeWithOptions(src, tt.opts)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, string(encode))
+		})
+	}
+
+	encode, err := Base64EncodeWithOptions([]byte("TrumanWong"), &Base64Options{Raw: true})
+	assert.NoError(t, err)
+	assert.Equal(t, "VHJ1bWFuV29uZw", string(encode))
+	encode, err = Base64EncodeWithOptions([]byte("TrumanWong"), &Base64Options{Alphabet: Base64URL})
+	assert.NoError(t, err)
+	assert.Equal(t, "VHJ1bWFuV29uZw==", string(encode))
+
+	_, err = Base64EncodeWithOptions(src, &Base64Options{Alphabet: "base64"})
+	assert.ErrorIs(t, err, ErrUnsupportedAlphabet)
+
+	// MIME lines of 76 characters
+	encode, err = Base64EncodeWithOptions(make([]byte, 120), &Base64Options{LineLength: 76})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("\r\n"), encode[76:78])
+	assert.Len(t, encode, 164)
+}
+
+func TestBase64DecodeWithOptions(t *testing.T) {
+	t.Run("TestBase64DecodeWithOptions", func(t *testing.T) {
+		ret, err := Base64DecodeWithOptions([]byte("VHJ1bWFuV29uZw"), &Base64Options{Alphabet: Base64URL, Raw: true})
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("TrumanWong"), ret)
+
+		// CR and LF are always ignored
+		ret, err = Base64DecodeWithOptions([]byte("VHJ1b\r\nWFuV2\r\n9uZw=="), nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("TrumanWong"), ret)
+
+		ret, err = Base64DecodeWithOptions([]byte(" VHJ1 bWFu\tV29u Zw==\n"), &Base64Options{Lenient: true})
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("TrumanWong"), ret)
+	})
+
+	t.Run("TestBase64DecodeWithOptionsInvalid", func(t *testing.T) {
+		_, err := Base64DecodeWithOptions([]byte("VHJ1 bWFuV29uZw=="), nil)
+		assert.Error(t, err)
+		_, err = Base64DecodeWithOptions([]byte("VHJ1bWFuV29uZw=="), &Base64Options{Raw: true})
+		assert.Error(t, err)
+		_, err = Base64DecodeWithOptions([]byte("VHJ1bWFuV29uZ_v_"), nil)
+		assert.Error(t, err)
+		_, err = Base64DecodeWithOptions([]byte("VHJ1bWFuV29uZw=="), &Base64Options{Alphabet: "base64"})
+		assert.ErrorIs(t, err, ErrUnsupportedAlphabet)
+	})
+}
+
+func TestBase64Stream(t *testing.T) {
+	src := make([]byte, 1000)
+	for i := range src {
+		src[i] = byte(i * 7)
+	}
+	for _, opts := range []*Base64Options{
+		nil,
+		{Alphabet: Base64URL, Raw: true},
+		{LineLength: 76},
+		{Alphabet: Base64URL, LineLength: 64, Lenient: true},
+	} {
+		var buf bytes.Buffer
+		w := NewBase64Encoder(&buf, opts)
+		for i := 0; i < len(src); i += 13 {
+			_, err := w.Write(src[i:min(i+13, len(src))])
+			assert.NoError(t, err)
+		}
+		assert.NoError(t, w.Close())
+		encode, err := Base64EncodeWithOptions(src, opts)
+		assert.NoError(t, err)
+		assert.Equal(t, encode, buf.Bytes())
+
+		ret, err := io.ReadAll(NewBase64Decoder(iotest.OneByteReader(&buf), opts))
+		assert.NoError(t, err)
+		assert.Equal(t, src, ret)
+	}
+
+	ret, err := io.ReadAll(NewBase64Decoder(strings.NewReader(" VHJ1 bWFu\tV29u Zw==\n"), &Base64Options{Lenient: true}))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("TrumanWong"), ret)
+
+	invalid := &Base64Options{Alphabet: "base64"}
+	w := NewBase64Encoder(io.Discard, invalid)
+	_, err = w.Write(src)
+	assert.ErrorIs(t, err, ErrUnsupportedAlphabet)
+	assert.ErrorIs(t, w.Close(), ErrUnsupportedAlphabet)
+	_, err = io.ReadAll(NewBase64Decoder(strings.NewReader("VHJ1bWFuV29uZw=="), invalid))
+	assert.ErrorIs(t, err, ErrUnsupportedAlphabet)
+}
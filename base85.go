@@ -0,0 +1,228 @@
+package cryptogo
+
+import (
+	"fmt"
+	"io"
+)
+
+// base85Encoding encodes each 4 bytes as 5 digits of an 85 character
+// alphabet, big-endian. A final group of n < 4 bytes is padded with zeros
+// and written as its first n+1 digits, as Python's b85encode and git do.
+type base85Encoding struct {
+	name     string
+	alphabet string
+	decode   [256]byte
+}
+
+func newBase85Encoding(name, alphabet string) *base85Encoding {
+	e := &base85Encoding{name: name, alphabet: alphabet}
+	for i := range e.decode {
+		e.decode[i] = 0xff
+	}
+	for i := 0; i < len(alphabet); i++ {
+		e.decode[alphabet[i]] = byte(i)
+	}
+	return e
+}
+
+func (e *base85Encoding) EncodedLen(n int) int {
+	if n%4 == 0 {
+		return n / 4 * 5
+	}
+	return n/4*5 + n%4 + 1
+}
+
+func (e *base85Encoding) DecodedLen(n int) int {
+	if n%5 == 0 {
+		return n / 5 * 4
+	}
+	return n/5*4 + n%5 - 1
+}
+
+// encode writes EncodedLen(len(src)) bytes to dst.
+func (e *base85Encoding) encode(dst, src []byte) {
+	for len(src) > 0 {
+		var group [4]byte
+		n := copy(group[:], src)
+		v := uint32(group[0])<<24 | uint32(group[1])<<16 | uint32(group[2])<<8 | uint32(group[3])
+		var digits [5]byte
+		for i := 4; i >= 0; i-- {
+			digits[i] = e.alphabet[v%85]
+			v /= 85
+		}
+		copy(dst, digits[:n+1])
+		dst, src = dst[n+1:], src[n:]
+	}
+}
+
+// decodeInto writes DecodedLen(len(src)) bytes to dst, offset is the
+// position of src in the whole input, for errors.
+func (e *base85Encoding) decodeInto(dst, src []byte, offset int64) error {
+	if len(src)%5 == 1 {
+		return fmt.Errorf("illegal %s data at input byte %d", e.name, offset+int64(len(src))-1)
+	}
+	for i := 0; i < len(src); i += 5 {
+		var v uint64
+		for j := i; j < i+5; j++ {
+			d := byte(84)
+			if j < len(src) {
+				if d = e.decode[src[j]]; d == 0xff {
+					return fmt.Errorf("illegal %s data at input byte %d", e.name, offset+int64(j))
+				}
+			}
+			v = v*85 + uint64(d)
+		}
+		if v > 0xffffffff {
+			return fmt.Errorf("illegal %s data at input byte %d", e.name, offset+int64(i))
+		}
+		group := [4]byte{byte(v >> 24), byte(v >> 16), byte(v >> 8), byte(v)}
+		dst = dst[copy(dst, group[:min(4, len(src)-i-1)]):]
+	}
+	return nil
+}
+
+func (e *base85Encoding) Encode(src []byte) []byte {
+	dst := make([]byte, e.EncodedLen(len(src)))
+	e.encode(dst, src)
+	return dst
+}
+
+func (e *base85Encoding) Decode(src []byte) ([]byte, error) {
+	dst := make([]byte, e.DecodedLen(len(src)))
+	if err := e.decodeInto(dst, src, 0); err != nil {
+		return nil, err
+	}
+	return dst, nil
+}
+
+func (e *base85Encoding) NewEncoder(w io.Writer) io.WriteCloser {
+	return &base85Encoder{enc: e, w: w}
+}
+
+func (e *base85Encoding) NewDecoder(r io.Reader) io.Reader {
+	return &base85Decoder{enc: e, r: r}
+}
+
+type base85Encoder struct {
+	enc  *base85Encoding
+	w    io.Writer
+	err  error
+	buf  [4]byte
+	nbuf int
+	out  [1000]byte
+}
+
+func (x *base85Encoder) Write(p []byte) (n int, err error) {
+	if x.err != nil {
+		return 0, x.err
+	}
+	// leading fringe
+	if x.nbuf > 0 {
+		var i int
+		for i = 0; i < len(p) && x.nbuf < 4; i++ {
+			x.buf[x.nbuf] = p[i]
+			x.nbuf++
+		}
+		n += i
+		p = p[i:]
+		if x.nbuf < 4 {
+			return n, nil
+		}
+		x.enc.encode(x.out[:], x.buf[:])
+		if _, x.err = x.w.Write(x.out[:5]); x.err != nil {
+			return n, x.err
+		}
+		x.nbuf = 0
+	}
+	// large interior chunks
+	for len(p) >= 4 {
+		nn := min(len(x.out)/5*4, len(p)&^3)
+		x.enc.encode(x.out[:], p[:nn])
+		if _, x.err = x.w.Write(x.out[:nn/4*5]); x.err != nil {
+			return n, x.err
+		}
+		n += nn
+		p = p[nn:]
+	}
+	// trailing fringe
+	copy(x.buf[:], p)
+	x.nbuf = len(p)
+	n += len(p)
+	return n, nil
+}
+
+// Close flushes any pending partial group. It does not close the
+// underlying writer.
+func (x *base85Encoder) Close() error {
+	if x.err == nil && x.nbuf > 0 {
+		x.enc.encode(x.out[:], x.buf[:x.nbuf])
+		_, x.err = x.w.Write(x.out[:x.nbuf+1])
+		x.nbuf = 0
+	}
+	return x.err
+}
+
+type base85Decoder struct {
+	enc    *base85Encoding
+	r      io.Reader
+	err    error
+	offset int64
+	buf    [1000]byte
+	nbuf   int
+	out    []byte
+	outbuf [1000 / 5 * 4]byte
+}
+
+func (d *base85Decoder) Read(p []byte) (n int, err error) {
+	for len(d.out) == 0 {
+		if d.err != nil {
+			return 0, d.err
+		}
+		var nn int
+		nn, d.err = d.r.Read(d.buf[d.nbuf:])
+		d.nbuf += nn
+		// whole groups, and the final partial group at the end
+		full := d.nbuf / 5 * 5
+		if d.err == io.EOF {
+			full = d.nbuf
+		}
+		if full == 0 {
+			continue
+		}
+		out := d.outbuf[:d.enc.DecodedLen(full)]
+		if err := d.enc.decodeInto(out, d.buf[:full], d.offset); err != nil {
+			d.err = err
+			return 0, err
+		}
+		d.out = out
+		d.offset += int64(full)
+		d.nbuf = copy(d.buf[:], d.buf[full:d.nbuf])
+	}
+	n = copy(p, d.out)
+	d.out = d.out[n:]
+	return n, nil
+}
+
+var base85 = newBase85Encoding("base85", "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz!#$%&()*+-;<=>?@^_`{|}~")
+
+// Base85Encode base85 encode with the RFC 1924 alphabet, as git binary
+// patches and Python's b85encode do.
+func Base85Encode(src []byte) []byte {
+	return base85.Encode(src)
+}
+
+// Base85Decode base85 decode with the RFC 1924 alphabet
+func Base85Decode(src []byte) ([]byte, error) {
+	return base85.Decode(src)
+}
+
+// NewBase85Encoder returns a streaming Base85Encode writing to w. Close must
+// be called to flush the last partial group.
+func NewBase85Encoder(w io.Writer) io.WriteCloser {
+	return base85.NewEncoder(w)
+}
+
+// NewBase85Decoder returns a streaming Base85Decode reading from r.
+func NewBase85Decoder(r io.Reader) io.Reader {
+	return base85.NewDecoder(r)
+}
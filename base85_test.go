@@ -0,0 +1,100 @@
+package cryptogo
+
+import (
+	"bytes"
+	"crypto/rand"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"testing"
+	"testing/iotest"
+)
+
+func ExampleBase85Encode() {
+	encode := Base85Encode([]byte("TrumanWong"))
+	fmt.Println(string(encode))
+	// Output: RC0A~VQyD%Zf5
+}
+
+func TestBase85Encode(t *testing.T) {
+	tests := []struct {
+		src      string
+		expected string
+	}{
+		{"", ""},
+		{"\x00\x00\x00\x00", "00000"},
+		{"\x00\x00\x00\x00a", "00000VE"},
+		{"TrumanWong", "RC0A~VQyD%Zf5"},
+		{"hello world!", "Xk~0{Zy<MXa%^NF"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			assert.Equal(t, tt.expected, string(Base85Encode([]byte(tt.src))))
+		})
+	}
+}
+
+func ExampleBase85Decode() {
+	decode, err := Base85Decode([]byte("RC0A~VQyD%Zf5"))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(string(decode))
+	// Output: TrumanWong
+}
+
+func TestBase85Decode(t *testing.T) {
+	t.Run("TestBase85Decode", func(t *testing.T) {
+		for i := 0; i < 16; i++ {
+			src := make([]byte, i)
+			_, err := rand.Read(src)
+			assert.NoError(t, err)
+			ret, err := Base85Decode(Base85Encode(src))
+			assert.NoError(t, err)
+			assert.Equal(t, src, ret)
+		}
+	})
+
+	t.Run("TestBase85DecodeInvalid", func(t *testing.T) {
+		// a lone last character, a character outside the alphabet and a
+		// group above 2^32-1
+		for _, src := range []string{"RC0A~V", "RC0A\"VQyD", "|NsC1", "~~"} {
+			_, err := Base85Decode([]byte(src))
+			assert.Error(t, err, src)
+		}
+		ret, err := Base85Decode([]byte("|NsC0"))
+		assert.NoError(t, err)
+		assert.Equal(t, []byte{0xff, 0xff, 0xff, 0xff}, ret)
+	})
+}
+
+func TestBase85Stream(t *testing.T) {
+	src := make([]byte, 4099)
+	_, err := rand.Read(src)
+	assert.NoError(t, err)
+
+	for _, step := range []int{1, 3, 4, 7, 1000, len(src)} {
+		var buf bytes.Buffer
+		w := NewBase85Encoder(&buf)
+		for i := 0; i < len(src); i += step {
+			_, err := w.Write(src[i:min(i+step, len(src))])
+			assert.NoError(t, err)
+		}
+		assert.NoError(t, w.Close())
+		assert.Equal(t, Base85Encode(src), buf.Bytes(), step)
+
+		ret, err := io.ReadAll(NewBase85Decoder(iotest.HalfReader(&buf)))
+		assert.NoError(t, err)
+		assert.Equal(t, src, ret, step)
+	}
+
+	ret, err := io.ReadAll(NewBase85Decoder(iotest.OneByteReader(bytes.NewReader(Base85Encode(src)))))
+	assert.NoError(t, err)
+	assert.Equal(t, src, ret)
+
+	encoded := Base85Encode(src)
+	encoded[2003] = '"'
+	_, err = io.ReadAll(NewBase85Decoder(bytes.NewReader(encoded)))
+	assert.EqualError(t, err, "illegal base85 data at input byte 2003")
+}
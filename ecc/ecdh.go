@@ -0,0 +1,129 @@
+package ecc
+
+import (
+	"crypto/ecdh"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"fmt"
+	"hash"
+	"io"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+type kdfAlgorithm string
+
+const (
+	// HKDF derives the key with RFC 5869 HKDF, using Salt and Info.
+	HKDF kdfAlgorithm = "hkdf"
+	// ConcatKDF derives the key with the NIST SP 800-56A concatenation KDF,
+	// using Info as OtherInfo.
+	ConcatKDF kdfAlgorithm = "concatkdf"
+)
+
+var ErrUnsupportedKDF = fmt.Errorf("ecc: unsupported key derivation function")
+
+// ECDHOptions controls the post-processing of the shared secret computed
+// by SharedSecret.
+type ECDHOptions struct {
+	// KDF applied to the shared secret. If empty, the raw shared secret is
+	// returned, which should not be used as a key directly.
+	KDF kdfAlgorithm
+	// Hash of the KDF, sha256.New by default.
+	Hash func() hash.Hash
+	// Salt of HKDF, ignored by ConcatKDF.
+	Salt []byte
+	// Info of HKDF or OtherInfo of ConcatKDF.
+	Info []byte
+	// KeyLen is the length of the derived key, the hash size by default.
+	KeyLen int
+}
+
+// SharedSecret performs ECDH on any crypto/ecdh curve (P-256, P-384, P-521
+// and X25519) and derives a key from the shared secret as selected by opts.
+// nil opts means the raw shared secret. The keys must be on the same curve;
+// an X25519 peer key of low order is rejected.
+func SharedSecret(prv *ecdh.PrivateKey, pub *ecdh.PublicKey, opts *ECDHOptions) ([]byte, error) {
+	z, err := prv.ECDH(pub)
+	if err != nil {
+		return nil, err
+	}
+	if opts == nil || opts.KDF == "" {
+		return z, nil
+	}
+
+	newHash := opts.Hash
+	if newHash == nil {
+		newHash = sha256.New
+	}
+	keyLen := opts.KeyLen
+	if keyLen <= 0 {
+		keyLen = newHash().Size()
+	}
+
+	switch opts.KDF {
+	case HKDF:
+		key := make([]byte, keyLen)
+		if _, err := io.ReadFull(hkdf.New(newHash, z, opts.Salt, opts.Info), key); err != nil {
+			return nil, err
+		}
+		return key, nil
+	case ConcatKDF:
+		if keyLen > maxKeyLen {
+			return nil, ErrInvalidKeyLen
+		}
+		return concatKDF(newHash(), z, opts.Info, keyLen), nil
+	}
+	return nil, ErrUnsupportedKDF
+}
+
+// ParseECDHPublicKey parses and validates a public key received from a
+// peer: an uncompressed point for the NIST curves, 32 bytes for X25519.
+// Points not on the curve and the point at infinity are rejected.
+func ParseECDHPublicKey(curve ecdh.Curve, b []byte) (*ecdh.PublicKey, error) {
+	pub, err := curve.NewPublicKey(b)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidPublicKey, err)
+	}
+	return pub, nil
+}
+
+// ECDHCurve returns the crypto/ecdh curve of a NIST elliptic curve.
+func ECDHCurve(curve elliptic.Curve) (ecdh.Curve, error) {
+	switch curve {
+	case elliptic.P256():
+		return ecdh.P256(), nil
+	case elliptic.P384():
+		return ecdh.P384(), nil
+	case elliptic.P521():
+		return ecdh.P521(), nil
+	}
+	return nil, ErrUnsupportedECDHAlgorithm
+}
+
+// ECDH converts the private key to a crypto/ecdh private key. Only the NIST
+// curves are supported.
+func (prv *PrivateKey) ECDH() (*ecdh.PrivateKey, error) {
+	curve, err := ECDHCurve(prv.Curve)
+	if err != nil {
+		return nil, err
+	}
+	size := (prv.Curve.Params().N.BitLen() + 7) / 8
+	if prv.D.BitLen() > size*8 {
+		return nil, ErrImport
+	}
+	return curve.NewPrivateKey(prv.D.FillBytes(make([]byte, size)))
+}
+
+// ECDH converts the public key to a crypto/ecdh public key, validating the
+// point. Only the NIST curves are supported.
+func (pub *PublicKey) ECDH() (*ecdh.PublicKey, error) {
+	curve, err := ECDHCurve(pub.Curve)
+	if err != nil {
+		return nil, err
+	}
+	if pub.X == nil || pub.Y == nil || !pub.Curve.IsOnCurve(pub.X, pub.Y) {
+		return nil, ErrInvalidPublicKey
+	}
+	return ParseECDHPublicKey(curve, elliptic.Marshal(pub.Curve, pub.X, pub.Y))
+}
@@ -0,0 +1,153 @@
+package ecc
+
+import (
+	"crypto/ecdh"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// test vector of RFC 7748, section 6.1
+const (
+	x25519AlicePrivate = "77076d0a7318a57d3c16c17251b26645df4c2f87ebc0992ab177fba51db92c2a"
+	x25519BobPublic    = "de9edb7d7b7dc1b4d35b61c2ece435373f8343c85b78674dadfc7e146f882b4f"
+	x25519Shared       = "4a5d9d5ba4ce2de1728e3bf480350f25e07e21c947d19e3376f09b3c1e161742"
+)
+
+func ExampleSharedSecret() {
+	alice, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		log.Fatal(err)
+	}
+	bob, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Bob sends his public key to Alice.
+	bobPub, err := ParseECDHPublicKey(ecdh.X25519(), bob.PublicKey().Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	opts := &ECDHOptions{KDF: HKDF, Info: []byte("trumanwong"), KeyLen: 32}
+	k1, err := SharedSecret(alice, bobPub, opts)
+	if err != nil {
+		log.Fatal(err)
+	}
+	k2, err := SharedSecret(bob, alice.PublicKey(), opts)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(len(k1), hex.EncodeToString(k1) == hex.EncodeToString(k2))
+	// Output: 32 true
+}
+
+func TestSharedSecretX25519(t *testing.T) {
+	b, _ := hex.DecodeString(x25519AlicePrivate)
+	alice, err := ecdh.X25519().NewPrivateKey(b)
+	assert.NoError(t, err)
+	b, _ = hex.DecodeString(x25519BobPublic)
+	bob, err := ParseECDHPublicKey(ecdh.X25519(), b)
+	assert.NoError(t, err)
+
+	z, err := SharedSecret(alice, bob, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, x25519Shared, hex.EncodeToString(z))
+
+	// low order point
+	zero, err := ParseECDHPublicKey(ecdh.X25519(), make([]byte, 32))
+	assert.NoError(t, err)
+	_, err = SharedSecret(alice, zero, nil)
+	assert.Error(t, err)
+
+	_, err = ParseECDHPublicKey(ecdh.X25519(), b[:31])
+	assert.ErrorIs(t, err, ErrInvalidPublicKey)
+}
+
+func TestSharedSecretKDF(t *testing.T) {
+	for _, curve := range []ecdh.Curve{ecdh.P256(), ecdh.P384(), ecdh.P521(), ecdh.X25519()} {
+		alice, err := curve.GenerateKey(rand.Reader)
+		assert.NoError(t, err)
+		bob, err := curve.GenerateKey(rand.Reader)
+		assert.NoError(t, err)
+
+		for _, opts := range []*ECDHOptions{
+			{KDF: HKDF},
+			{KDF: HKDF, Hash: sha512.New, Salt: []byte("salt"), KeyLen: 80},
+			{KDF: ConcatKDF, Info: []byte("trumanwong"), KeyLen: 16},
+			{KDF: ConcatKDF, Hash: sha512.New384, KeyLen: 100},
+		} {
+			k1, err := SharedSecret(alice, bob.PublicKey(), opts)
+			assert.NoError(t, err)
+			k2, err := SharedSecret(bob, alice.PublicKey(), opts)
+			assert.NoError(t, err)
+			assert.Equal(t, k1, k2)
+			if opts.KeyLen > 0 {
+				assert.Len(t, k1, opts.KeyLen)
+			} else {
+				assert.Len(t, k1, 32)
+			}
+		}
+
+		hkdf, _ := SharedSecret(alice, bob.PublicKey(), &ECDHOptions{KDF: HKDF})
+		concat, _ := SharedSecret(alice, bob.PublicKey(), &ECDHOptions{KDF: ConcatKDF})
+		info, _ := SharedSecret(alice, bob.PublicKey(), &ECDHOptions{KDF: HKDF, Info: []byte("other")})
+		assert.NotEqual(t, hkdf, concat)
+		assert.NotEqual(t, hkdf, info)
+
+		_, err = SharedSecret(alice, bob.PublicKey(), &ECDHOptions{KDF: "pbkdf2"})
+		assert.ErrorIs(t, err, ErrUnsupportedKDF)
+	}
+
+	p256, _ := ecdh.P256().GenerateKey(rand.Reader)
+	x25519, _ := ecdh.X25519().GenerateKey(rand.Reader)
+	_, err := SharedSecret(p256, x25519.PublicKey(), nil)
+	assert.Error(t, err)
+}
+
+func TestECDHConversion(t *testing.T) {
+	for _, curve := range []elliptic.Curve{elliptic.P256(), elliptic.P384(), elliptic.P521()} {
+		prv1, err := GenerateKey(rand.Reader, curve, nil)
+		assert.NoError(t, err)
+		prv2, err := GenerateKey(rand.Reader, curve, nil)
+		assert.NoError(t, err)
+
+		e1, err := prv1.ECDH()
+		assert.NoError(t, err)
+		e2, err := prv2.PublicKey.ECDH()
+		assert.NoError(t, err)
+		assert.Equal(t, elliptic.Marshal(curve, prv1.X, prv1.Y), e1.PublicKey().Bytes())
+
+		z, err := SharedSecret(e1, e2, nil)
+		assert.NoError(t, err)
+		sk, err := prv1.GenerateShared(&prv2.PublicKey, len(z)/2, len(z)-len(z)/2)
+		assert.NoError(t, err)
+		assert.Equal(t, z, sk)
+	}
+
+	prv, err := GenerateKey(rand.Reader, S256(), nil)
+	assert.NoError(t, err)
+	_, err = prv.ECDH()
+	assert.ErrorIs(t, err, ErrUnsupportedECDHAlgorithm)
+
+	// not on the curve
+	p256, err := GenerateKey(rand.Reader, elliptic.P256(), nil)
+	assert.NoError(t, err)
+	pub := p256.PublicKey
+	pub.X = pub.Y
+	_, err = pub.ECDH()
+	assert.ErrorIs(t, err, ErrInvalidPublicKey)
+	_, err = p256.GenerateShared(&pub, 16, 16)
+	assert.ErrorIs(t, err, ErrInvalidPublicKey)
+
+	// the point at infinity
+	_, err = ParseECDHPublicKey(ecdh.P256(), []byte{0})
+	assert.ErrorIs(t, err, ErrInvalidPublicKey)
+}
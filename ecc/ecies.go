@@ -126,15 +126,34 @@ func (prv *PrivateKey) GenerateShared(pub *PublicKey, skLen, macLen int) (sk []b
 		return nil, ErrSharedKeyTooBig
 	}
 
-	x, _ := pub.Curve.ScalarMult(pub.X, pub.Y, prv.D.Bytes())
-	if x == nil {
-		return nil, ErrSharedKeyIsPointAtInfinity
+	if err := pub.Validate(); err != nil {
+		return nil, err
+	}
+
+	var skBytes []byte
+	if _, err := ECDHCurve(pub.Curve); err == nil {
+		// crypto/ecdh is constant time.
+		ep, err := prv.ECDH()
+		if err != nil {
+			return nil, err
+		}
+		epub, err := pub.ECDH()
+		if err != nil {
+			return nil, err
+		}
+		if skBytes, err = SharedSecret(ep, epub, nil); err != nil {
+			return nil, ErrSharedKeyIsPointAtInfinity
+		}
+	} else {
+		x, _, err := sharedPoint(prv, pub)
+		if err != nil {
+			return nil, err
+		}
+		skBytes = x.FillBytes(make([]byte, MaxSharedKeyLength(pub)))
 	}
 
-	sk = make([]byte, skLen+macLen)
-	skBytes := x.Bytes()
-	copy(sk[len(sk)-len(skBytes):], skBytes)
-	return sk, nil
+	// the rightmost bytes of the x-coordinate
+	return skBytes[len(skBytes)-(skLen+macLen):], nil
 }
 
 var (
@@ -230,6 +249,11 @@ func symDecrypt(params *ECIESParams, key, ct []byte) (m []byte, err error) {
 // ciphertext. s1 is fed into key derivation, s2 is fed into the MAC. If the
 // shared information parameters aren't being used, they should be nil.
 func Encrypt(rand io.Reader, pub *PublicKey, m, s1, s2 []byte) (ct []byte, err error) {
+	return encrypt(rand, pub, m, s1, s2, false)
+}
+
+// encrypt is Encrypt with the ephemeral public key optionally compressed.
+func encrypt(rand io.Reader, pub *PublicKey, m, s1, s2 []byte, compressed bool) (ct []byte, err error) {
 	params, err := pubkeyParams(pub)
 	if err != nil {
 		return nil, err
@@ -240,7 +264,9 @@ func Encrypt(rand io.Reader, pub *PublicKey, m, s1, s2 []byte) (ct []byte, err e
 		return nil, err
 	}
 
-	z, err := R.GenerateShared(pub, params.KeyLen, params.KeyLen)
+	// The whole x-coordinate is the KDF input, which is what the former
+	// GenerateShared(pub, KeyLen, KeyLen) returned for the default params.
+	z, err := R.GenerateShared(pub, MaxSharedKeyLength(pub), 0)
 	if err != nil {
 		return nil, err
 	}
@@ -255,7 +281,7 @@ func Encrypt(rand io.Reader, pub *PublicKey, m, s1, s2 []byte) (ct []byte, err e
 
 	d := messageTag(params.Hash, Km, em, s2)
 
-	Rb := elliptic.Marshal(pub.Curve, R.PublicKey.X, R.PublicKey.Y)
+	Rb := marshalPoint(R.Curve, R.X, R.Y, compressed)
 	ct = make([]byte, len(Rb)+len(em)+len(d))
 	copy(ct, Rb)
 	copy(ct[len(Rb):], em)
@@ -263,7 +289,8 @@ func Encrypt(rand io.Reader, pub *PublicKey, m, s1, s2 []byte) (ct []byte, err e
 	return ct, nil
 }
 
-// Decrypt decrypts an ECIES ciphertext.
+// Decrypt decrypts an ECIES ciphertext. The ephemeral public key may be
+// compressed or uncompressed.
 func (prv *PrivateKey) Decrypt(c, s1, s2 []byte) (m []byte, err error) {
 	if len(c) == 0 {
 		return nil, ErrInvalidMessage
@@ -282,27 +309,22 @@ func (prv *PrivateKey) Decrypt(c, s1, s2 []byte) (m []byte, err error) {
 		mEnd   int
 	)
 
-	switch c[0] {
-	case 2, 3, 4:
-		rLen = (prv.PublicKey.Curve.Params().BitSize + 7) / 4
-		if len(c) < (rLen + hLen + 1) {
-			return nil, ErrInvalidMessage
-		}
-	default:
+	if rLen = ephemeralPointLen(prv.PublicKey.Curve, c); rLen == 0 {
 		return nil, ErrInvalidPublicKey
 	}
+	if len(c) < (rLen + hLen + 1) {
+		return nil, ErrInvalidMessage
+	}
 
 	mStart = rLen
 	mEnd = len(c) - hLen
 
-	R := new(PublicKey)
-	R.Curve = prv.PublicKey.Curve
-	R.X, R.Y = elliptic.Unmarshal(R.Curve, c[:rLen])
-	if R.X == nil {
-		return nil, ErrInvalidPublicKey
+	R, err := UnmarshalPublicKey(prv.PublicKey.Curve, c[:rLen])
+	if err != nil {
+		return nil, err
 	}
 
-	z, err := prv.GenerateShared(R, params.KeyLen, params.KeyLen)
+	z, err := prv.GenerateShared(R, MaxSharedKeyLength(R), 0)
 	if err != nil {
 		return nil, err
 	}
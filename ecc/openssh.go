@@ -0,0 +1,57 @@
+package ecc
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+
+	"github.com/trumanwong/cryptogo/openssh"
+)
+
+var ErrInvalidSSHKey = fmt.Errorf("ecc: key is not an ecdsa ssh key")
+
+// MarshalAuthorizedKey encodes the public key as an OpenSSH authorized_keys
+// line ("ecdsa-sha2-nistp256 AAAA... comment"). Only P-256, P-384 and
+// P-521 keys have an OpenSSH encoding.
+func (pub *PublicKey) MarshalAuthorizedKey(comment string) ([]byte, error) {
+	return openssh.MarshalAuthorizedKey(pub.ExportECDSA(), comment)
+}
+
+// SSHFingerprint returns the SHA256 fingerprint of the public key as
+// ssh-keygen -l prints it.
+func (pub *PublicKey) SSHFingerprint() (string, error) {
+	return openssh.Fingerprint(pub.ExportECDSA())
+}
+
+// ParseAuthorizedKey parses an ecdsa-sha2-* authorized_keys line and returns
+// the public key and its comment.
+func ParseAuthorizedKey(in []byte) (*PublicKey, string, error) {
+	pub, comment, err := openssh.ParseAuthorizedKey(in)
+	if err != nil {
+		return nil, "", err
+	}
+	key, ok := pub.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, "", ErrInvalidSSHKey
+	}
+	return ImportECDSAPublic(key), comment, nil
+}
+
+// MarshalOpenSSH encodes the private key as an OPENSSH PRIVATE KEY pem,
+// encrypted with passphrase unless it is empty.
+func (prv *PrivateKey) MarshalOpenSSH(comment string, passphrase []byte) ([]byte, error) {
+	return openssh.MarshalPrivateKey(prv.ExportECDSA(), comment, passphrase)
+}
+
+// ParseOpenSSHPrivateKey parses an OPENSSH PRIVATE KEY pem holding an ecdsa
+// key, decrypting it with passphrase if needed.
+func ParseOpenSSHPrivateKey(in, passphrase []byte) (*PrivateKey, error) {
+	prv, err := openssh.ParsePrivateKey(in, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := prv.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, ErrInvalidSSHKey
+	}
+	return ImportECDSA(key), nil
+}
@@ -111,7 +111,7 @@ var (
 )
 
 var paramsFromCurve = map[elliptic.Curve]*ECIESParams{
-	//ethcrypto.S256(): ECIES_AES128_SHA256,
+	S256():          ECIES_AES128_SHA256,
 	elliptic.P256(): ECIES_AES128_SHA256,
 	elliptic.P384(): ECIES_AES192_SHA384,
 	elliptic.P521(): ECIES_AES256_SHA512,
@@ -122,7 +122,7 @@ func AddParamsForCurve(curve elliptic.Curve, params *ECIESParams) {
 }
 
 // ParamsFromCurve selects parameters optimal for the selected elliptic curve.
-// Only the curves P256, P384, and P512 are supported.
+// Only the curves secp256k1, P256, P384, and P512 are supported.
 func ParamsFromCurve(curve elliptic.Curve) (params *ECIESParams) {
 	return paramsFromCurve[curve]
 }
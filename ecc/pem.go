@@ -0,0 +1,388 @@
+package ecc
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"math/big"
+)
+
+type keyFormat string
+
+const (
+	SEC1  keyFormat = "sec1"
+	PKCS8 keyFormat = "pkcs8"
+)
+
+// eciesParamsHeader is the pem header carrying the name of the ECIESParams
+// of a key, since SEC1, PKCS8 and PKIX have no room for it. It is only
+// written when the params differ from ParamsFromCurve, as OpenSSL refuses
+// pem headers other than its own encryption headers.
+const eciesParamsHeader = "ECIES-Params"
+
+var (
+	ErrInvalidKeyFormat = fmt.Errorf("ecc: invalid key format")
+	ErrInvalidPEM       = fmt.Errorf("ecc: key is invalid pem")
+	ErrNotECKey         = fmt.Errorf("ecc: key is not an elliptic curve key")
+)
+
+var paramsNames = map[*ECIESParams]string{
+	ECIES_AES128_SHA256: "ECIES_AES128_SHA256",
+	ECIES_AES192_SHA384: "ECIES_AES192_SHA384",
+	ECIES_AES256_SHA256: "ECIES_AES256_SHA256",
+	ECIES_AES256_SHA384: "ECIES_AES256_SHA384",
+	ECIES_AES256_SHA512: "ECIES_AES256_SHA512",
+}
+
+// GenerateKeyPair Generate an elliptic curve key pair and return the private
+// key pem with format (SEC1 or PKCS8) and the PKIX public key pem, like
+// rsa.GenerateKeyPair. If params is nil, the recommended default parameters
+// for the curve are used.
+func GenerateKeyPair(rand io.Reader, curve elliptic.Curve, params *ECIESParams, format keyFormat) (privateKey, publicKey []byte, err error) {
+	prv, err := GenerateKey(rand, curve, params)
+	if err != nil {
+		return nil, nil, err
+	}
+	privateKey, err = prv.MarshalPEM(format)
+	if err != nil {
+		return nil, nil, err
+	}
+	publicKey, err = prv.PublicKey.MarshalPEM()
+	if err != nil {
+		return nil, nil, err
+	}
+	return privateKey, publicKey, nil
+}
+
+// MarshalSEC1 encodes the private key as SEC1 (RFC 5915) der.
+func (prv *PrivateKey) MarshalSEC1() ([]byte, error) {
+	if prv.Curve == S256() {
+		return marshalECPrivateKey(prv, oidNamedCurveS256)
+	}
+	return x509.MarshalECPrivateKey(prv.ExportECDSA())
+}
+
+// MarshalPKCS8 encodes the private key as PKCS8 der.
+func (prv *PrivateKey) MarshalPKCS8() ([]byte, error) {
+	if prv.Curve == S256() {
+		return marshalECPKCS8(prv, oidNamedCurveS256)
+	}
+	return x509.MarshalPKCS8PrivateKey(prv.ExportECDSA())
+}
+
+// MarshalPKIX encodes the public key as PKIX der.
+func (pub *PublicKey) MarshalPKIX() ([]byte, error) {
+	if pub.Curve == S256() {
+		return marshalECPKIX(pub, oidNamedCurveS256)
+	}
+	return x509.MarshalPKIXPublicKey(pub.ExportECDSA())
+}
+
+// MarshalPEM encodes the private key as an "EC PRIVATE KEY" (SEC1) or
+// "PRIVATE KEY" (PKCS8) pem. Standard ECIESParams other than the curve
+// default are kept in a pem header.
+func (prv *PrivateKey) MarshalPEM(format keyFormat) ([]byte, error) {
+	var block *pem.Block
+	switch format {
+	case SEC1:
+		der, err := prv.MarshalSEC1()
+		if err != nil {
+			return nil, err
+		}
+		block = &pem.Block{Type: "EC PRIVATE KEY", Bytes: der}
+	case PKCS8:
+		der, err := prv.MarshalPKCS8()
+		if err != nil {
+			return nil, err
+		}
+		block = &pem.Block{Type: "PRIVATE KEY", Bytes: der}
+	default:
+		return nil, ErrInvalidKeyFormat
+	}
+	setParamsHeader(block, &prv.PublicKey)
+	return pem.EncodeToMemory(block), nil
+}
+
+// MarshalPEM encodes the public key as a "PUBLIC KEY" (PKIX) pem. Standard
+// ECIESParams other than the curve default are kept in a pem header.
+func (pub *PublicKey) MarshalPEM() ([]byte, error) {
+	der, err := pub.MarshalPKIX()
+	if err != nil {
+		return nil, err
+	}
+	block := &pem.Block{Type: "PUBLIC KEY", Bytes: der}
+	setParamsHeader(block, pub)
+	return pem.EncodeToMemory(block), nil
+}
+
+// ParsePrivateKey parses an "EC PRIVATE KEY" or "PRIVATE KEY" pem. The
+// ECIESParams are restored from the pem header, or chosen from the curve.
+func ParsePrivateKey(data []byte) (*PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, ErrInvalidPEM
+	}
+	var prv *PrivateKey
+	var err error
+	switch block.Type {
+	case "EC PRIVATE KEY":
+		prv, err = parseSEC1(block.Bytes)
+	case "PRIVATE KEY":
+		prv, err = parsePKCS8(block.Bytes)
+	default:
+		return nil, ErrInvalidPEM
+	}
+	if err != nil {
+		return nil, err
+	}
+	if params := paramsFromHeader(block); params != nil {
+		prv.PublicKey.Params = params
+	}
+	return prv, nil
+}
+
+// ParsePublicKey parses a "PUBLIC KEY" pem. The ECIESParams are restored
+// from the pem header, or chosen from the curve.
+func ParsePublicKey(data []byte) (*PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "PUBLIC KEY" {
+		return nil, ErrInvalidPEM
+	}
+	pub, err := ParsePKIX(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	if params := paramsFromHeader(block); params != nil {
+		pub.Params = params
+	}
+	return pub, nil
+}
+
+// ParsePrivateKeyDER parses a SEC1 or PKCS8 der private key.
+func ParsePrivateKeyDER(der []byte) (*PrivateKey, error) {
+	if prv, err := parseSEC1(der); err == nil {
+		return prv, nil
+	}
+	return parsePKCS8(der)
+}
+
+// ParsePKIX parses a PKIX der public key. Compressed points, which OpenSSL
+// writes with -conv_form compressed, and secp256k1 keys are accepted too.
+func ParsePKIX(der []byte) (*PublicKey, error) {
+	key, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		if pub, ok := parseECPKIX(der); ok {
+			return pub, nil
+		}
+		return nil, err
+	}
+	pub, ok := key.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, ErrNotECKey
+	}
+	return ImportECDSAPublic(pub), nil
+}
+
+func parseSEC1(der []byte) (*PrivateKey, error) {
+	key, err := x509.ParseECPrivateKey(der)
+	if err != nil {
+		if prv, ok := parseECPrivateKey(der, nil); ok {
+			return prv, nil
+		}
+		return nil, err
+	}
+	return ImportECDSA(key), nil
+}
+
+func parsePKCS8(der []byte) (*PrivateKey, error) {
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		if prv, ok := parseECPKCS8(der); ok {
+			return prv, nil
+		}
+		return nil, err
+	}
+	prv, ok := key.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, ErrNotECKey
+	}
+	return ImportECDSA(prv), nil
+}
+
+func setParamsHeader(block *pem.Block, pub *PublicKey) {
+	if pub.Params == nil || pub.Params == ParamsFromCurve(pub.Curve) {
+		return
+	}
+	if name, ok := paramsNames[pub.Params]; ok {
+		block.Headers = map[string]string{eciesParamsHeader: name}
+	}
+}
+
+func paramsFromHeader(block *pem.Block) *ECIESParams {
+	name, ok := block.Headers[eciesParamsHeader]
+	if !ok {
+		return nil
+	}
+	for params, n := range paramsNames {
+		if n == name {
+			return params
+		}
+	}
+	return nil
+}
+
+var (
+	oidPublicKeyECDSA = asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}
+	oidNamedCurveS256 = asn1.ObjectIdentifier{1, 3, 132, 0, 10}
+	oidNamedCurves    = map[string]elliptic.Curve{
+		"1.2.840.10045.3.1.7":      elliptic.P256(),
+		"1.3.132.0.34":             elliptic.P384(),
+		"1.3.132.0.35":             elliptic.P521(),
+		oidNamedCurveS256.String(): S256(),
+	}
+)
+
+// ecPrivateKey is the ECPrivateKey of RFC 5915, for the curves crypto/x509
+// doesn't know.
+type ecPrivateKey struct {
+	Version       int
+	PrivateKey    []byte
+	NamedCurveOID asn1.ObjectIdentifier `asn1:"optional,explicit,tag:0"`
+	PublicKey     asn1.BitString        `asn1:"optional,explicit,tag:1"`
+}
+
+// pkcs8 is the PrivateKeyInfo of RFC 5208.
+type pkcs8 struct {
+	Version    int
+	Algo       pkix.AlgorithmIdentifier
+	PrivateKey []byte
+}
+
+// subjectPublicKeyInfo is the SubjectPublicKeyInfo of RFC 5280.
+type subjectPublicKeyInfo struct {
+	Algorithm pkix.AlgorithmIdentifier
+	PublicKey asn1.BitString
+}
+
+// ecAlgorithm is the id-ecPublicKey AlgorithmIdentifier with the named
+// curve oid.
+func ecAlgorithm(oid asn1.ObjectIdentifier) (pkix.AlgorithmIdentifier, error) {
+	params, err := asn1.Marshal(oid)
+	if err != nil {
+		return pkix.AlgorithmIdentifier{}, err
+	}
+	return pkix.AlgorithmIdentifier{Algorithm: oidPublicKeyECDSA, Parameters: asn1.RawValue{FullBytes: params}}, nil
+}
+
+// marshalECPrivateKey is x509.MarshalECPrivateKey for the curves it
+// doesn't know. The oid is omitted when nil, as in PKCS8.
+func marshalECPrivateKey(prv *PrivateKey, oid asn1.ObjectIdentifier) ([]byte, error) {
+	size := (prv.Curve.Params().N.BitLen() + 7) / 8
+	point := marshalPoint(prv.Curve, prv.X, prv.Y, false)
+	return asn1.Marshal(ecPrivateKey{
+		Version:       1,
+		PrivateKey:    prv.D.FillBytes(make([]byte, size)),
+		NamedCurveOID: oid,
+		PublicKey:     asn1.BitString{Bytes: point, BitLength: 8 * len(point)},
+	})
+}
+
+// marshalECPKCS8 is x509.MarshalPKCS8PrivateKey for the curves it doesn't
+// know.
+func marshalECPKCS8(prv *PrivateKey, oid asn1.ObjectIdentifier) ([]byte, error) {
+	algo, err := ecAlgorithm(oid)
+	if err != nil {
+		return nil, err
+	}
+	key, err := marshalECPrivateKey(prv, nil)
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(pkcs8{Algo: algo, PrivateKey: key})
+}
+
+// marshalECPKIX is x509.MarshalPKIXPublicKey for the curves it doesn't
+// know.
+func marshalECPKIX(pub *PublicKey, oid asn1.ObjectIdentifier) ([]byte, error) {
+	algo, err := ecAlgorithm(oid)
+	if err != nil {
+		return nil, err
+	}
+	point := marshalPoint(pub.Curve, pub.X, pub.Y, false)
+	return asn1.Marshal(subjectPublicKeyInfo{
+		Algorithm: algo,
+		PublicKey: asn1.BitString{Bytes: point, BitLength: 8 * len(point)},
+	})
+}
+
+// parseECPrivateKey parses the SEC1 private keys crypto/x509 refuses, on
+// curves it doesn't know. oid is the curve of the PKCS8 wrapper, if any.
+func parseECPrivateKey(der []byte, oid asn1.ObjectIdentifier) (*PrivateKey, bool) {
+	var key ecPrivateKey
+	if rest, err := asn1.Unmarshal(der, &key); err != nil || len(rest) != 0 || key.Version != 1 {
+		return nil, false
+	}
+	if len(key.NamedCurveOID) != 0 {
+		oid = key.NamedCurveOID
+	}
+	curve, ok := oidNamedCurves[oid.String()]
+	if !ok {
+		return nil, false
+	}
+	d := new(big.Int).SetBytes(key.PrivateKey)
+	if len(key.PrivateKey) != (curve.Params().N.BitLen()+7)/8 || d.Sign() == 0 || d.Cmp(curve.Params().N) >= 0 {
+		return nil, false
+	}
+	prv := &PrivateKey{D: d}
+	prv.PublicKey.Curve = curve
+	prv.PublicKey.X, prv.PublicKey.Y = curve.ScalarBaseMult(key.PrivateKey)
+	prv.PublicKey.Params = ParamsFromCurve(curve)
+	return prv, true
+}
+
+// parseECPKCS8 parses the PKCS8 private keys crypto/x509 refuses, on curves
+// it doesn't know.
+func parseECPKCS8(der []byte) (*PrivateKey, bool) {
+	var key pkcs8
+	if rest, err := asn1.Unmarshal(der, &key); err != nil || len(rest) != 0 {
+		return nil, false
+	}
+	if !key.Algo.Algorithm.Equal(oidPublicKeyECDSA) {
+		return nil, false
+	}
+	var oid asn1.ObjectIdentifier
+	if _, err := asn1.Unmarshal(key.Algo.Parameters.FullBytes, &oid); err != nil {
+		return nil, false
+	}
+	return parseECPrivateKey(key.PrivateKey, oid)
+}
+
+// parseECPKIX parses the PKIX public keys crypto/x509 refuses: compressed
+// points and curves it doesn't know.
+func parseECPKIX(der []byte) (*PublicKey, bool) {
+	var spki subjectPublicKeyInfo
+	if rest, err := asn1.Unmarshal(der, &spki); err != nil || len(rest) != 0 {
+		return nil, false
+	}
+	if !spki.Algorithm.Algorithm.Equal(oidPublicKeyECDSA) {
+		return nil, false
+	}
+	var oid asn1.ObjectIdentifier
+	if _, err := asn1.Unmarshal(spki.Algorithm.Parameters.FullBytes, &oid); err != nil {
+		return nil, false
+	}
+	curve, ok := oidNamedCurves[oid.String()]
+	if !ok {
+		return nil, false
+	}
+	pub, err := UnmarshalPublicKey(curve, spki.PublicKey.RightAlign())
+	if err != nil {
+		return nil, false
+	}
+	return pub, true
+}
@@ -0,0 +1,108 @@
+package ecc
+
+import (
+	"crypto/elliptic"
+	"math/big"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+// PointFormat is the SEC1 encoding of a point, used by PublicKey.Marshal
+// and EncryptOptions.
+type PointFormat string
+
+const (
+	// Uncompressed is the SEC1 point 0x04 || x || y.
+	Uncompressed PointFormat = "uncompressed"
+	// Compressed is the SEC1 point 0x02 or 0x03 (the parity of y) || x.
+	Compressed PointFormat = "compressed"
+)
+
+// Marshal encodes the public key as a SEC1 point in format, Uncompressed
+// unless format is Compressed.
+func (pub *PublicKey) Marshal(format PointFormat) []byte {
+	return marshalPoint(pub.Curve, pub.X, pub.Y, format == Compressed)
+}
+
+// UnmarshalPublicKey decodes a SEC1 compressed or uncompressed point on
+// curve. Points not on the curve and the point at infinity are rejected.
+func UnmarshalPublicKey(curve elliptic.Curve, b []byte) (*PublicKey, error) {
+	x, y := unmarshalPoint(curve, b)
+	if x == nil {
+		return nil, ErrInvalidPublicKey
+	}
+	return &PublicKey{X: x, Y: y, Curve: curve, Params: ParamsFromCurve(curve)}, nil
+}
+
+// Validate checks that the public key is a point on its curve and not the
+// point at infinity. Keys from ImportECDSAPublic are not checked, so keys
+// received from peers should be validated before use.
+func (pub *PublicKey) Validate() error {
+	if pub == nil || pub.Curve == nil || pub.X == nil || pub.Y == nil {
+		return ErrInvalidPublicKey
+	}
+	if pub.X.Sign() == 0 && pub.Y.Sign() == 0 {
+		return ErrInvalidPublicKey
+	}
+	if !pub.Curve.IsOnCurve(pub.X, pub.Y) {
+		return ErrInvalidPublicKey
+	}
+	return nil
+}
+
+// marshalPoint encodes a point in the SEC1 uncompressed or compressed form.
+func marshalPoint(curve elliptic.Curve, x, y *big.Int, compressed bool) []byte {
+	if compressed {
+		return elliptic.MarshalCompressed(curve, x, y)
+	}
+	return elliptic.Marshal(curve, x, y)
+}
+
+// unmarshalPoint decodes a SEC1 uncompressed or compressed point and checks
+// that it is on the curve. The point at infinity is rejected.
+func unmarshalPoint(curve elliptic.Curve, b []byte) (x, y *big.Int) {
+	if len(b) == 0 {
+		return nil, nil
+	}
+	byteLen := (curve.Params().BitSize + 7) / 8
+	switch {
+	case b[0] == 4 && len(b) == 1+2*byteLen:
+		return elliptic.Unmarshal(curve, b)
+	case (b[0] == 2 || b[0] == 3) && len(b) == 1+byteLen:
+		if curve == S256() {
+			// elliptic.UnmarshalCompressed assumes a = -3.
+			pub, err := secp256k1.ParsePubKey(b)
+			if err != nil {
+				return nil, nil
+			}
+			p := pub.ToECDSA()
+			return p.X, p.Y
+		}
+		return elliptic.UnmarshalCompressed(curve, b)
+	}
+	return nil, nil
+}
+
+// pointLen returns the length of a SEC1 encoded point.
+func pointLen(curve elliptic.Curve, compressed bool) int {
+	byteLen := (curve.Params().BitSize + 7) / 8
+	if compressed {
+		return 1 + byteLen
+	}
+	return 1 + 2*byteLen
+}
+
+// ephemeralPointLen returns the length of the SEC1 point at the start of c
+// from its prefix, or 0 if the prefix is invalid.
+func ephemeralPointLen(curve elliptic.Curve, c []byte) int {
+	if len(c) == 0 {
+		return 0
+	}
+	switch c[0] {
+	case 2, 3:
+		return pointLen(curve, true)
+	case 4:
+		return pointLen(curve, false)
+	}
+	return 0
+}
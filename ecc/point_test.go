@@ -0,0 +1,160 @@
+package ecc
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// generated with openssl ec -pubout -conv_form compressed
+const (
+	opensslCompressedP256 = `-----BEGIN PUBLIC KEY-----
+MDkwEwYHKoZIzj0CAQYIKoZIzj0DAQcDIgACH+wbyVg8LAg7vxHHWHUGsqoSnoZt
+0pn5wDREndIzu0s=
+-----END PUBLIC KEY-----
+`
+	opensslUncompressedP256 = `-----BEGIN PUBLIC KEY-----
+MFkwEwYHKoZIzj0CAQYIKoZIzj0DAQcDQgAEH+wbyVg8LAg7vxHHWHUGsqoSnoZt
+0pn5wDREndIzu0veTrP0ZalC7CKPcqbbOz8vpbIB8ohGWLidFMI5C+CtMg==
+-----END PUBLIC KEY-----
+`
+	opensslCompressedS256 = `-----BEGIN PUBLIC KEY-----
+MDYwEAYHKoZIzj0CAQYFK4EEAAoDIgADenjDD3o6B01gZD6r7MjJuozXBR3GkER+
+eNF0dxCGrUs=
+-----END PUBLIC KEY-----
+`
+	opensslPointS256 = "047a78c30f7a3a074d60643eabecc8c9ba8cd7051dc690447e78d174771086ad4b295070c591589583dc06a73142e25b942c2e6434c5779cf89c2c7e24fb4986cb"
+)
+
+func ExampleUnmarshalPublicKey() {
+	prv, err := GenerateKey(rand.Reader, S256(), nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	b := prv.PublicKey.Marshal(Compressed)
+	pub, err := UnmarshalPublicKey(S256(), b)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(len(b), pub.X.Cmp(prv.X) == 0 && pub.Y.Cmp(prv.Y) == 0)
+	// Output: 33 true
+}
+
+func TestMarshalPoint(t *testing.T) {
+	for _, curve := range []elliptic.Curve{S256(), elliptic.P256(), elliptic.P384(), elliptic.P521()} {
+		for i := 0; i < 8; i++ {
+			prv, err := GenerateKey(rand.Reader, curve, nil)
+			assert.NoError(t, err)
+			for _, format := range []PointFormat{Compressed, Uncompressed} {
+				b := prv.PublicKey.Marshal(format)
+				assert.Len(t, b, pointLen(curve, format == Compressed))
+				pub, err := UnmarshalPublicKey(curve, b)
+				assert.NoError(t, err)
+				assert.Equal(t, prv.X, pub.X)
+				assert.Equal(t, prv.Y, pub.Y)
+				assert.NoError(t, pub.Validate())
+			}
+		}
+	}
+}
+
+func TestUnmarshalInvalidPoint(t *testing.T) {
+	prv, err := GenerateKey(rand.Reader, S256(), nil)
+	assert.NoError(t, err)
+	size := MaxSharedKeyLength(&prv.PublicKey)
+
+	notOnCurve := prv.PublicKey.Marshal(Uncompressed)
+	notOnCurve[len(notOnCurve)-1] ^= 1
+	identity := make([]byte, 1+2*size)
+	identity[0] = 4
+	tooBig := append([]byte{2}, S256().Params().P.Bytes()...)
+	wrongPrefix := prv.PublicKey.Marshal(Compressed)
+	wrongPrefix[0] = 5
+
+	for _, b := range [][]byte{nil, {0}, notOnCurve, identity, tooBig, wrongPrefix, prv.PublicKey.Marshal(Compressed)[:size]} {
+		_, err := UnmarshalPublicKey(S256(), b)
+		assert.ErrorIs(t, err, ErrInvalidPublicKey, hex.EncodeToString(b))
+	}
+
+	pub := ImportECDSAPublic(&ecdsa.PublicKey{Curve: elliptic.P256(), X: big.NewInt(1), Y: big.NewInt(1)})
+	assert.ErrorIs(t, pub.Validate(), ErrInvalidPublicKey)
+	assert.ErrorIs(t, (&PublicKey{}).Validate(), ErrInvalidPublicKey)
+	_, err = Encrypt(rand.Reader, pub, []byte("trumanwong"), nil, nil)
+	assert.ErrorIs(t, err, ErrInvalidPublicKey)
+	_, err = EncryptWithOptions(rand.Reader, pub, []byte("trumanwong"), &EncryptOptions{Profile: AESGCM})
+	assert.ErrorIs(t, err, ErrInvalidPublicKey)
+
+	// an ephemeral key that is not on the curve
+	ct, err := Encrypt(rand.Reader, &prv.PublicKey, []byte("trumanwong"), nil, nil)
+	assert.NoError(t, err)
+	ct[1+2*size-1] ^= 1
+	_, err = prv.Decrypt(ct, nil, nil)
+	assert.ErrorIs(t, err, ErrInvalidPublicKey)
+}
+
+func TestParseCompressedPKIX(t *testing.T) {
+	compressed, err := ParsePublicKey([]byte(opensslCompressedP256))
+	assert.NoError(t, err)
+	uncompressed, err := ParsePublicKey([]byte(opensslUncompressedP256))
+	assert.NoError(t, err)
+	assert.Equal(t, uncompressed.X, compressed.X)
+	assert.Equal(t, uncompressed.Y, compressed.Y)
+	assert.Equal(t, ECIES_AES128_SHA256, compressed.Params)
+
+	pub, err := ParsePublicKey([]byte(opensslCompressedS256))
+	assert.NoError(t, err)
+	assert.Equal(t, S256(), pub.Curve)
+	assert.Equal(t, opensslPointS256, hex.EncodeToString(pub.Marshal(Uncompressed)))
+}
+
+func TestEncryptCompressed(t *testing.T) {
+	src := []byte("trumanwong")
+	tests := []struct {
+		curve  elliptic.Curve
+		params *ECIESParams
+	}{
+		{S256(), nil},
+		{elliptic.P256(), nil},
+		{elliptic.P384(), nil},
+		{elliptic.P521(), nil},
+		{elliptic.P256(), ECIES_AES256_SHA256},
+		{elliptic.P384(), ECIES_AES128_SHA256},
+		{elliptic.P521(), ECIES_AES256_SHA384},
+	}
+	for _, tt := range tests {
+		prv, err := GenerateKey(rand.Reader, tt.curve, tt.params)
+		assert.NoError(t, err)
+		for _, format := range []PointFormat{Uncompressed, Compressed} {
+			for _, p := range []profile{Legacy, ECIESJS, X963, AESGCM, ChaCha20Poly1305} {
+				opts := &EncryptOptions{Profile: p, PointFormat: format}
+				ct, err := EncryptWithOptions(rand.Reader, &prv.PublicKey, src, opts)
+				assert.NoError(t, err)
+				assert.Len(t, ct[:pointLen(tt.curve, format == Compressed)], ephemeralPointLen(tt.curve, ct))
+
+				// the point format is detected when decrypting
+				pt, err := prv.DecryptWithOptions(ct, &EncryptOptions{Profile: p})
+				assert.NoError(t, err)
+				assert.Equal(t, src, pt)
+			}
+		}
+
+		ct, err := EncryptWithOptions(rand.Reader, &prv.PublicKey, src, &EncryptOptions{PointFormat: Compressed})
+		assert.NoError(t, err)
+		pt, err := prv.Decrypt(ct, nil, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, src, pt)
+	}
+
+	prv, err := GenerateKey(rand.Reader, elliptic.P256(), nil)
+	assert.NoError(t, err)
+	_, err = EncryptWithOptions(rand.Reader, &prv.PublicKey, src, &EncryptOptions{PointFormat: "hybrid"})
+	assert.ErrorIs(t, err, ErrUnsupportedProfile)
+}
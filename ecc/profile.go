@@ -0,0 +1,321 @@
+package ecc
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"io"
+	"math/big"
+
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/hkdf"
+)
+
+type profile string
+
+const (
+	// Legacy is the format of Encrypt: uncompressed ephemeral key, NIST
+	// concatenation KDF, AES-CTR with a random IV and HMAC.
+	Legacy profile = "legacy"
+	// ECIESJS is the format of eciesjs and eciespy: uncompressed ephemeral
+	// key || 16 byte nonce || tag || AES-256-GCM ciphertext, keyed with
+	// HKDF-SHA256 of the uncompressed ephemeral key and shared point. Those
+	// libraries use secp256k1 (S256).
+	ECIESJS profile = "eciesjs"
+	// X963 is ECIES of SEC1 v2, section 5.1: compressed ephemeral key ||
+	// AES-CTR ciphertext with a zero IV || HMAC tag, with the ANSI X9.63 KDF.
+	// The cipher, key length and hash are taken from the ECIESParams.
+	X963 profile = "x963"
+	// AESGCM is compressed ephemeral key || 12 byte nonce || AES-256-GCM
+	// ciphertext and tag, keyed with HKDF-SHA256 of the shared secret and
+	// salted with the ephemeral key.
+	AESGCM profile = "aes-gcm"
+	// ChaCha20Poly1305 is AESGCM with ChaCha20-Poly1305 as the AEAD.
+	ChaCha20Poly1305 profile = "chacha20-poly1305"
+)
+
+var ErrUnsupportedProfile = fmt.Errorf("ecies: unsupported profile")
+
+// EncryptOptions selects the ECIES profile of EncryptWithOptions and
+// DecryptWithOptions. SharedInfo1 is fed into the key derivation (the HKDF
+// info of the AEAD profiles) and SharedInfo2 into the MAC (the additional
+// data of the AEAD profiles); ECIESJS supports neither. PointFormat of the
+// ephemeral key defaults to Uncompressed for Legacy and ECIESJS and to
+// Compressed for the others; decryption accepts both. Nil options mean the
+// Legacy profile.
+type EncryptOptions struct {
+	Profile     profile
+	SharedInfo1 []byte
+	SharedInfo2 []byte
+	PointFormat PointFormat
+}
+
+// compressed reports whether the ephemeral key is compressed.
+func (opts *EncryptOptions) compressed() (bool, error) {
+	switch opts.PointFormat {
+	case "":
+		return opts.Profile != "" && opts.Profile != Legacy && opts.Profile != ECIESJS, nil
+	case Compressed:
+		return true, nil
+	case Uncompressed:
+		return false, nil
+	}
+	return false, ErrUnsupportedProfile
+}
+
+const eciesjsNonceSize = 16
+
+// EncryptWithOptions encrypts m to pub with the profile in opts.
+func EncryptWithOptions(rand io.Reader, pub *PublicKey, m []byte, opts *EncryptOptions) ([]byte, error) {
+	if opts == nil {
+		opts = &EncryptOptions{}
+	}
+	compressed, err := opts.compressed()
+	if err != nil {
+		return nil, err
+	}
+	switch opts.Profile {
+	case "", Legacy:
+		return encrypt(rand, pub, m, opts.SharedInfo1, opts.SharedInfo2, compressed)
+	case ECIESJS, X963, AESGCM, ChaCha20Poly1305:
+	default:
+		return nil, ErrUnsupportedProfile
+	}
+
+	params, err := pubkeyParams(pub)
+	if err != nil {
+		return nil, err
+	}
+	R, err := GenerateKey(rand, pub.Curve, params)
+	if err != nil {
+		return nil, err
+	}
+	sx, sy, err := sharedPoint(R, pub)
+	if err != nil {
+		return nil, err
+	}
+
+	switch opts.Profile {
+	case ECIESJS:
+		if opts.SharedInfo1 != nil || opts.SharedInfo2 != nil {
+			return nil, ErrUnsupportedProfile
+		}
+		Rb := marshalPoint(R.Curve, R.X, R.Y, compressed)
+		aead, err := eciesjsAEAD(Rb, marshalPoint(pub.Curve, sx, sy, false))
+		if err != nil {
+			return nil, err
+		}
+		nonce := make([]byte, eciesjsNonceSize)
+		if _, err := io.ReadFull(rand, nonce); err != nil {
+			return nil, err
+		}
+		sealed := aead.Seal(nil, nonce, m, nil)
+		tag := sealed[len(sealed)-aead.Overhead():]
+		ct := make([]byte, 0, len(Rb)+len(nonce)+len(sealed))
+		ct = append(append(append(append(ct, Rb...), nonce...), tag...), sealed[:len(m)]...)
+		return ct, nil
+	case X963:
+		Rb := marshalPoint(R.Curve, R.X, R.Y, compressed)
+		z := sx.FillBytes(make([]byte, MaxSharedKeyLength(pub)))
+		ke, km := x963Keys(params, z, opts.SharedInfo1)
+		em, err := x963XOR(params, ke, m)
+		if err != nil {
+			return nil, err
+		}
+		mac := hmac.New(params.Hash, km)
+		mac.Write(em)
+		mac.Write(opts.SharedInfo2)
+		ct := make([]byte, 0, len(Rb)+len(em)+mac.Size())
+		return mac.Sum(append(append(ct, Rb...), em...)), nil
+	}
+
+	// AESGCM and ChaCha20Poly1305
+	Rb := marshalPoint(R.Curve, R.X, R.Y, compressed)
+	z := sx.FillBytes(make([]byte, MaxSharedKeyLength(pub)))
+	aead, err := profileAEAD(opts.Profile, z, Rb, opts.SharedInfo1)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand, nonce); err != nil {
+		return nil, err
+	}
+	ct := make([]byte, 0, len(Rb)+len(nonce)+len(m)+aead.Overhead())
+	ct = append(append(ct, Rb...), nonce...)
+	return aead.Seal(ct, nonce, m, opts.SharedInfo2), nil
+}
+
+// DecryptWithOptions decrypts a ciphertext of EncryptWithOptions.
+func (prv *PrivateKey) DecryptWithOptions(c []byte, opts *EncryptOptions) ([]byte, error) {
+	if opts == nil {
+		opts = &EncryptOptions{}
+	}
+	switch opts.Profile {
+	case "", Legacy:
+		return prv.Decrypt(c, opts.SharedInfo1, opts.SharedInfo2)
+	case ECIESJS:
+		if opts.SharedInfo1 != nil || opts.SharedInfo2 != nil {
+			return nil, ErrUnsupportedProfile
+		}
+	case X963, AESGCM, ChaCha20Poly1305:
+	default:
+		return nil, ErrUnsupportedProfile
+	}
+
+	params, err := pubkeyParams(&prv.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	rLen := ephemeralPointLen(prv.Curve, c)
+	if rLen == 0 {
+		return nil, ErrInvalidPublicKey
+	}
+	if len(c) < rLen {
+		return nil, ErrInvalidMessage
+	}
+	R, err := UnmarshalPublicKey(prv.Curve, c[:rLen])
+	if err != nil {
+		return nil, err
+	}
+	sx, sy, err := sharedPoint(prv, R)
+	if err != nil {
+		return nil, err
+	}
+	Rb, c := c[:rLen], c[rLen:]
+
+	switch opts.Profile {
+	case ECIESJS:
+		aead, err := eciesjsAEAD(Rb, marshalPoint(prv.Curve, sx, sy, false))
+		if err != nil {
+			return nil, err
+		}
+		if len(c) < eciesjsNonceSize+aead.Overhead() {
+			return nil, ErrInvalidMessage
+		}
+		nonce, tag, em := c[:eciesjsNonceSize], c[eciesjsNonceSize:eciesjsNonceSize+aead.Overhead()], c[eciesjsNonceSize+aead.Overhead():]
+		sealed := append(append([]byte{}, em...), tag...)
+		m, err := aead.Open(sealed[:0], nonce, sealed, nil)
+		if err != nil {
+			return nil, ErrInvalidMessage
+		}
+		return m, nil
+	case X963:
+		hLen := params.Hash().Size()
+		if len(c) < hLen {
+			return nil, ErrInvalidMessage
+		}
+		em, d := c[:len(c)-hLen], c[len(c)-hLen:]
+		z := sx.FillBytes(make([]byte, MaxSharedKeyLength(&prv.PublicKey)))
+		ke, km := x963Keys(params, z, opts.SharedInfo1)
+		mac := hmac.New(params.Hash, km)
+		mac.Write(em)
+		mac.Write(opts.SharedInfo2)
+		if subtle.ConstantTimeCompare(mac.Sum(nil), d) != 1 {
+			return nil, ErrInvalidMessage
+		}
+		return x963XOR(params, ke, em)
+	}
+
+	z := sx.FillBytes(make([]byte, MaxSharedKeyLength(&prv.PublicKey)))
+	aead, err := profileAEAD(opts.Profile, z, Rb, opts.SharedInfo1)
+	if err != nil {
+		return nil, err
+	}
+	if len(c) < aead.NonceSize()+aead.Overhead() {
+		return nil, ErrInvalidMessage
+	}
+	m, err := aead.Open(nil, c[:aead.NonceSize()], c[aead.NonceSize():], opts.SharedInfo2)
+	if err != nil {
+		return nil, ErrInvalidMessage
+	}
+	return m, nil
+}
+
+// sharedPoint computes the ECDH shared point of prv and pub, which must be
+// a point on the curve of prv.
+func sharedPoint(prv *PrivateKey, pub *PublicKey) (x, y *big.Int, err error) {
+	if prv.Curve != pub.Curve {
+		return nil, nil, ErrInvalidCurve
+	}
+	if err := pub.Validate(); err != nil {
+		return nil, nil, err
+	}
+	x, y = pub.Curve.ScalarMult(pub.X, pub.Y, prv.D.Bytes())
+	if x == nil || (x.Sign() == 0 && y.Sign() == 0) {
+		return nil, nil, ErrSharedKeyIsPointAtInfinity
+	}
+	return x, y, nil
+}
+
+// eciesjsAEAD returns the AES-256-GCM of eciesjs, keyed with
+// HKDF-SHA256(R || S) where R and S are uncompressed.
+func eciesjsAEAD(Rb, Sb []byte) (cipher.AEAD, error) {
+	key := make([]byte, 32)
+	master := append(append([]byte{}, Rb...), Sb...)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, master, nil, nil), key); err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCMWithNonceSize(block, eciesjsNonceSize)
+}
+
+// profileAEAD returns the AEAD of the AESGCM and ChaCha20Poly1305 profiles,
+// keyed with HKDF-SHA256(z, salt = R, info = s1).
+func profileAEAD(p profile, z, Rb, s1 []byte) (cipher.AEAD, error) {
+	key := make([]byte, 32)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, z, Rb, s1), key); err != nil {
+		return nil, err
+	}
+	if p == ChaCha20Poly1305 {
+		return chacha20poly1305.New(key)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// x963KDF is the key derivation function of ANSI X9.63 (SEC1 v2, section
+// 3.6.1), which unlike concatKDF puts the counter after z.
+func x963KDF(h hash.Hash, z, sharedInfo []byte, kdLen int) []byte {
+	counterBytes := make([]byte, 4)
+	k := make([]byte, 0, roundup(kdLen, h.Size()))
+	for counter := uint32(1); len(k) < kdLen; counter++ {
+		binary.BigEndian.PutUint32(counterBytes, counter)
+		h.Reset()
+		h.Write(z)
+		h.Write(counterBytes)
+		h.Write(sharedInfo)
+		k = h.Sum(k)
+	}
+	return k[:kdLen]
+}
+
+// x963Keys derives the encryption key of params.KeyLen bytes and the MAC
+// key of the hash size.
+func x963Keys(params *ECIESParams, z, s1 []byte) (ke, km []byte) {
+	h := params.Hash()
+	k := x963KDF(h, z, s1, params.KeyLen+h.Size())
+	return k[:params.KeyLen], k[params.KeyLen:]
+}
+
+// x963XOR encrypts or decrypts with the block cipher of params in CTR mode
+// with a zero IV, which is safe as the key is never reused.
+func x963XOR(params *ECIESParams, key, src []byte) ([]byte, error) {
+	block, err := params.Cipher(key)
+	if err != nil {
+		return nil, err
+	}
+	dst := make([]byte, len(src))
+	cipher.NewCTR(block, make([]byte, params.BlockSize)).XORKeyStream(dst, src)
+	return dst, nil
+}
@@ -0,0 +1,136 @@
+package ecc
+
+import (
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// profileTestKey is the recipient of profileTests, the ciphertexts of
+// "trumanwong" with random ephemeral keys and nonces. SharedInfo1 is "s1"
+// and SharedInfo2 "s2" for all but ECIESJS.
+//
+// The X963 ones were generated by testdata/profile_x963.sh with the OpenSSL
+// 3.0.17 command line tool, including its X963KDF. The others were generated
+// by testdata/profile_vectors.js with Node.js 20 crypto: the ECIESJS ones
+// reimplement the eciesjs format and are to be replaced by the output of the
+// real eciesjs (testdata/eciesjs), which only supports secp256k1. AESGCM and
+// ChaCha20Poly1305 are formats of this package, so no third-party library
+// produces them.
+const profileTestKey = "2c7d4e0b6c1dbf0e3aa3cd2d9d1c2b8d4f6a0b9e1c7d3f5a8b2e4c6d8f0a1b3c"
+
+var profileTests = []struct {
+	curve   elliptic.Curve
+	profile profile
+	ct      string
+}{
+	{S256(), ECIESJS, "048660221da92d46ad7ad224a66726c51368b16f421504b1b1234cc90f67de0d63a842ac0df05bcaa926ad59451ab8d7ddd8dc1ca75ae6fe5956c658bb8657ad413b0ba8977d84662f883d849c1054b3a9d35eddba9fd37005e8f1a76d45b34830f4973e665f214576fd80"},
+	{S256(), X963, "028585d8931e14a4b8f1528f8cfc46dad437e334c3cf0df2913ed216585366b61c8d0c8b16369cbbd332c62cbda844c719a54dceee63ed6f3c2eb5d8253928742c2a236a19aa1acd774841"},
+	{S256(), AESGCM, "0212a3c32403c786b558aaba8aad515fded1aecac4b02138835de2e5fab4b81ad880d8a3e6f5777ce6dceaa6b1fff3f2bf5a7c2f899122065800014157b44c58f0f44ef7d24a26"},
+	{S256(), ChaCha20Poly1305, "02abcd96c01026a54c1f4dd583573d0ee5cc455aaa79b98abd95ed60ad428ab8932c8e0640711b806a2999b6d5818da0e75f017fd6ecbf62efa023402027b54872fcd053a807e1"},
+	{elliptic.P256(), ECIESJS, "0415fe5d59c9575f2552adfbee6b705c7d622fa1be2a0193de4b245a572eb958cbe309e83d99dc323801e44310bbeafa24f2af9eebd28619773c127cbf91e2906a91edd0e1342826dcfb37db04931062bb7a05e1aedf2b193faf49501a8e6f83a8f7b485686e302885f13c"},
+	{elliptic.P256(), X963, "0395c3e52a7a2e4398cf4eb23b96fd406d36fda8e70c7b8a849ebc99de38293430dda4da5f5d39c8586dd1cc467372cdfb4e89d57083a4188aaefdbc9506f667c23f15e58720f2d35419a3"},
+	{elliptic.P256(), AESGCM, "0394d55fb3c120170cd8ea3c6ae1b2185ffca7f944439ed67e99148cdc4b81717d7318157df018d3f81862798b7030bf64ec59796593ba0abf74fc5a5c2f14bb07e61ed3f6b573"},
+	{elliptic.P256(), ChaCha20Poly1305, "02de478aa1703d8a2a86df2baf671a1678ab33394828ca8d84ffa58cc2766c811e74d6a191271e3572ba7b6fd0518cb442f216ddb975aaa8fc18aa7c9e6116a2519eddcdcb61d3"},
+}
+
+func ExampleEncryptWithOptions() {
+	prv, err := GenerateKey(rand.Reader, S256(), nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	opts := &EncryptOptions{Profile: ECIESJS}
+	ct, err := EncryptWithOptions(rand.Reader, &prv.PublicKey, []byte("trumanwong"), opts)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	pt, err := prv.DecryptWithOptions(ct, opts)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(string(pt))
+	// Output: trumanwong
+}
+
+func TestProfileVectors(t *testing.T) {
+	for _, tt := range profileTests {
+		t.Run(fmt.Sprintf("%s/%s", tt.curve.Params().Name, tt.profile), func(t *testing.T) {
+			prv := keyFromHex(tt.curve, profileTestKey)
+			ct, _ := hex.DecodeString(tt.ct)
+			opts := &EncryptOptions{Profile: tt.profile}
+			if tt.profile != ECIESJS {
+				opts.SharedInfo1, opts.SharedInfo2 = []byte("s1"), []byte("s2")
+			}
+
+			pt, err := prv.DecryptWithOptions(ct, opts)
+			assert.NoError(t, err)
+			assert.Equal(t, "trumanwong", string(pt))
+
+			ct[len(ct)-1] ^= 1
+			_, err = prv.DecryptWithOptions(ct, opts)
+			assert.ErrorIs(t, err, ErrInvalidMessage)
+		})
+	}
+}
+
+func TestEncryptWithOptions(t *testing.T) {
+	src := []byte("trumanwong")
+	for _, curve := range []elliptic.Curve{S256(), elliptic.P256(), elliptic.P384(), elliptic.P521()} {
+		prv, err := GenerateKey(rand.Reader, curve, nil)
+		assert.NoError(t, err)
+		other, err := GenerateKey(rand.Reader, curve, nil)
+		assert.NoError(t, err)
+
+		for _, p := range []profile{ECIESJS, X963, AESGCM, ChaCha20Poly1305} {
+			t.Run(fmt.Sprintf("%s/%s", curve.Params().Name, p), func(t *testing.T) {
+				opts := &EncryptOptions{Profile: p}
+				ct, err := EncryptWithOptions(rand.Reader, &prv.PublicKey, src, opts)
+				assert.NoError(t, err)
+				if p == ECIESJS {
+					assert.Equal(t, byte(4), ct[0])
+				} else {
+					assert.Contains(t, []byte{2, 3}, ct[0])
+				}
+
+				pt, err := prv.DecryptWithOptions(ct, opts)
+				assert.NoError(t, err)
+				assert.Equal(t, src, pt)
+
+				_, err = other.DecryptWithOptions(ct, opts)
+				assert.Error(t, err)
+				_, err = prv.DecryptWithOptions(ct[:10], opts)
+				assert.Error(t, err)
+
+				if p != ECIESJS {
+					opts.SharedInfo1, opts.SharedInfo2 = []byte("s1"), []byte("s2")
+					ct, err := EncryptWithOptions(rand.Reader, &prv.PublicKey, src, opts)
+					assert.NoError(t, err)
+					_, err = prv.DecryptWithOptions(ct, &EncryptOptions{Profile: p, SharedInfo1: []byte("s1")})
+					assert.ErrorIs(t, err, ErrInvalidMessage)
+					_, err = prv.DecryptWithOptions(ct, &EncryptOptions{Profile: p, SharedInfo2: []byte("s2")})
+					assert.ErrorIs(t, err, ErrInvalidMessage)
+				}
+			})
+		}
+	}
+
+	prv, err := GenerateKey(rand.Reader, elliptic.P256(), nil)
+	assert.NoError(t, err)
+	ct, err := EncryptWithOptions(rand.Reader, &prv.PublicKey, src, nil)
+	assert.NoError(t, err)
+	pt, err := prv.Decrypt(ct, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, src, pt)
+
+	_, err = EncryptWithOptions(rand.Reader, &prv.PublicKey, src, &EncryptOptions{Profile: "rsa"})
+	assert.ErrorIs(t, err, ErrUnsupportedProfile)
+	_, err = EncryptWithOptions(rand.Reader, &prv.PublicKey, src, &EncryptOptions{Profile: ECIESJS, SharedInfo1: []byte("s1")})
+	assert.ErrorIs(t, err, ErrUnsupportedProfile)
+}
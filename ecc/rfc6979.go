@@ -0,0 +1,223 @@
+package ecc
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"hash"
+	"math/big"
+)
+
+type signatureEncoding string
+
+const (
+	ASN1  signatureEncoding = "asn1"
+	P1363 signatureEncoding = "p1363"
+)
+
+// SignOptions controls SignWithOptions and VerifyWithOptions.
+type SignOptions struct {
+	// Encoding of the signature, ASN1 by default.
+	Encoding signatureEncoding
+	// Deterministic derives the nonce from the key and the message as
+	// specified in RFC 6979 instead of reading it from rand.Reader.
+	Deterministic bool
+	// LowS normalizes s to the lower half of the curve order when signing
+	// (BIP-62) and rejects signatures with a high s when verifying.
+	LowS bool
+}
+
+// SignWithOptions signs the newHash digest of src as selected by opts. nil
+// opts means a randomized ASN.1 DER signature.
+func SignWithOptions(p *PrivateKey, src []byte, newHash func() hash.Hash, opts *SignOptions) ([]byte, error) {
+	if opts == nil {
+		opts = &SignOptions{}
+	}
+	h := newHash()
+	h.Write(src)
+	digest := h.Sum(nil)
+
+	var r, s *big.Int
+	var err error
+	if opts.Deterministic {
+		r, s, err = signRFC6979(p, digest, newHash)
+	} else {
+		r, s, err = ecdsa.Sign(rand.Reader, p.ExportECDSA(), digest)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if opts.LowS {
+		s = normalizeS(p.Curve, s)
+	}
+
+	switch opts.Encoding {
+	case "", ASN1:
+		return marshalASN1(r, s)
+	case P1363:
+		return marshalP1363(p.Curve, r, s), nil
+	}
+	return nil, ErrInvalidSignature
+}
+
+// VerifyWithOptions verifies a signature of the newHash digest of src
+// encoded as selected by opts.
+func VerifyWithOptions(p *PublicKey, src, sig []byte, newHash func() hash.Hash, opts *SignOptions) bool {
+	if opts == nil {
+		opts = &SignOptions{}
+	}
+	var r, s *big.Int
+	var err error
+	switch opts.Encoding {
+	case "", ASN1:
+		r, s, err = parseASN1(sig)
+	case P1363:
+		r, s, err = parseP1363(p.Curve, sig)
+	default:
+		return false
+	}
+	if err != nil {
+		return false
+	}
+	if opts.LowS && !IsLowS(p.Curve, s) {
+		return false
+	}
+
+	h := newHash()
+	h.Write(src)
+	return ecdsa.Verify(p.ExportECDSA(), h.Sum(nil), r, s)
+}
+
+// IsLowS reports whether s is at most half the order of curve.
+func IsLowS(curve elliptic.Curve, s *big.Int) bool {
+	halfOrder := new(big.Int).Rsh(curve.Params().N, 1)
+	return s.Cmp(halfOrder) <= 0
+}
+
+// NormalizeSignature rewrites an ASN.1 DER or IEEE P1363 signature to its
+// low-S form, so that signatures produced elsewhere pass a LowS check.
+func NormalizeSignature(curve elliptic.Curve, sig []byte, encoding signatureEncoding) ([]byte, error) {
+	switch encoding {
+	case "", ASN1:
+		r, s, err := parseASN1(sig)
+		if err != nil {
+			return nil, err
+		}
+		return marshalASN1(r, normalizeS(curve, s))
+	case P1363:
+		r, s, err := parseP1363(curve, sig)
+		if err != nil {
+			return nil, err
+		}
+		return marshalP1363(curve, r, normalizeS(curve, s)), nil
+	}
+	return nil, ErrInvalidSignature
+}
+
+func normalizeS(curve elliptic.Curve, s *big.Int) *big.Int {
+	if IsLowS(curve, s) {
+		return s
+	}
+	return new(big.Int).Sub(curve.Params().N, s)
+}
+
+// signRFC6979 computes an ECDSA signature of digest with the nonce
+// generated as in RFC 6979, section 3.2.
+func signRFC6979(p *PrivateKey, digest []byte, newHash func() hash.Hash) (r, s *big.Int, err error) {
+	n := p.Curve.Params().N
+	e := bits2int(digest, n.BitLen())
+	k := newNonceGenerator(p, digest, newHash)
+	for {
+		nonce := k.next()
+		x, _ := p.Curve.ScalarBaseMult(nonce.FillBytes(make([]byte, (n.BitLen()+7)/8)))
+		r = new(big.Int).Mod(x, n)
+		if r.Sign() == 0 {
+			continue
+		}
+		// s = k^-1 * (e + r*d) mod n
+		s = new(big.Int).Mul(r, p.D)
+		s.Add(s, e)
+		s.Mul(s, new(big.Int).ModInverse(nonce, n))
+		s.Mod(s, n)
+		if s.Sign() != 0 {
+			return r, s, nil
+		}
+	}
+}
+
+// nonceGenerator is the HMAC_DRBG of RFC 6979, section 3.2.
+type nonceGenerator struct {
+	newHash func() hash.Hash
+	n       *big.Int
+	k, v    []byte
+}
+
+func newNonceGenerator(p *PrivateKey, digest []byte, newHash func() hash.Hash) *nonceGenerator {
+	n := p.Curve.Params().N
+	rlen := (n.BitLen() + 7) / 8
+	hlen := newHash().Size()
+
+	g := &nonceGenerator{newHash: newHash, n: n}
+	g.v = make([]byte, hlen)
+	for i := range g.v {
+		g.v[i] = 0x01
+	}
+	g.k = make([]byte, hlen)
+
+	x := p.D.FillBytes(make([]byte, rlen))
+	h1 := bits2octets(digest, n)
+	for _, b := range []byte{0x00, 0x01} {
+		g.k = g.mac(g.v, []byte{b}, x, h1)
+		g.v = g.mac(g.v)
+	}
+	return g
+}
+
+func (g *nonceGenerator) mac(data ...[]byte) []byte {
+	m := hmac.New(g.newHash, g.k)
+	for _, d := range data {
+		m.Write(d)
+	}
+	return m.Sum(nil)
+}
+
+// next returns the next candidate nonce in [1, n-1].
+func (g *nonceGenerator) next() *big.Int {
+	qlen := g.n.BitLen()
+	for {
+		var t []byte
+		for len(t)*8 < qlen {
+			g.v = g.mac(g.v)
+			t = append(t, g.v...)
+		}
+		k := bits2int(t, qlen)
+		// Prepare the state for a further candidate in case this one, or
+		// the signature it yields, is rejected.
+		g.k = g.mac(g.v, []byte{0x00})
+		g.v = g.mac(g.v)
+		if k.Sign() > 0 && k.Cmp(g.n) < 0 {
+			return k
+		}
+	}
+}
+
+// bits2int converts the leftmost qlen bits of b to an integer.
+func bits2int(b []byte, qlen int) *big.Int {
+	v := new(big.Int).SetBytes(b)
+	if blen := len(b) * 8; blen > qlen {
+		v.Rsh(v, uint(blen-qlen))
+	}
+	return v
+}
+
+// bits2octets converts digest to an integer reduced modulo n and encodes it
+// in rlen bytes.
+func bits2octets(digest []byte, n *big.Int) []byte {
+	z1 := bits2int(digest, n.BitLen())
+	z2 := new(big.Int).Sub(z1, n)
+	if z2.Sign() < 0 {
+		z2 = z1
+	}
+	return z2.FillBytes(make([]byte, (n.BitLen()+7)/8))
+}
@@ -0,0 +1,109 @@
+package ecc
+
+import (
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"hash"
+	"log"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func hexInt(s string) *big.Int {
+	v, _ := new(big.Int).SetString(s, 16)
+	return v
+}
+
+func keyFromHex(curve elliptic.Curve, d string) *PrivateKey {
+	prv := &PrivateKey{D: hexInt(d)}
+	prv.PublicKey.Curve = curve
+	prv.PublicKey.X, prv.PublicKey.Y = curve.ScalarBaseMult(prv.D.Bytes())
+	prv.PublicKey.Params = ParamsFromCurve(curve)
+	return prv
+}
+
+func ExampleSignWithOptions() {
+	prv := keyFromHex(elliptic.P256(), "C9AFA9D845BA75166B5C215767B1D6934E50C3DB36E89B127B8A622B120F6721")
+	sig, err := SignWithOptions(prv, []byte("sample"), sha256.New, &SignOptions{Encoding: P1363, Deterministic: true})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(strings.ToUpper(hex.EncodeToString(sig)))
+	// Output: EFD48B2AACB6A8FD1140DD9CD45E81D69D2C877B56AAF991C34D0EA84EAF3716F7CB1C942D657C41D436C7A1B6E29F65F3E900DBB9AFF4064DC4AB2F843ACDA8
+}
+
+func TestSignRFC6979(t *testing.T) {
+	// RFC 6979, appendix A.2.5 and A.2.6.
+	tests := []struct {
+		Curve   elliptic.Curve
+		D       string
+		Hash    func() hash.Hash
+		Message string
+		R, S    string
+	}{
+		{
+			elliptic.P256(), "C9AFA9D845BA75166B5C215767B1D6934E50C3DB36E89B127B8A622B120F6721", sha256.New, "sample",
+			"EFD48B2AACB6A8FD1140DD9CD45E81D69D2C877B56AAF991C34D0EA84EAF3716",
+			"F7CB1C942D657C41D436C7A1B6E29F65F3E900DBB9AFF4064DC4AB2F843ACDA8",
+		},
+		{
+			elliptic.P256(), "C9AFA9D845BA75166B5C215767B1D6934E50C3DB36E89B127B8A622B120F6721", sha256.New, "test",
+			"F1ABB023518351CD71D881567B1EA663ED3EFCF6C5132B354F28D3B0B7D38367",
+			"019F4113742A2B14BD25926B49C649155F267E60D3814B4C0CC84250E46F0083",
+		},
+		{
+			elliptic.P384(), "6B9D3DAD2E1B8C1C05B19875B6659F4DE23C3B667BF297BA9AA47740787137D896D5724E4C70A825F872C9EA60D2EDF5", sha512.New384, "sample",
+			"94EDBB92A5ECB8AAD4736E56C691916B3F88140666CE9FA73D64C4EA95AD133C81A648152E44ACF96E36DD1E80FABE46",
+			"99EF4AEB15F178CEA1FE40DB2603138F130E740A19624526203B6351D0A3A94FA329C145786E679E7B82C71A38628AC8",
+		},
+	}
+	for _, v := range tests {
+		t.Run(fmt.Sprintf("%s-%s", v.Curve.Params().Name, v.Message), func(t *testing.T) {
+			prv := keyFromHex(v.Curve, v.D)
+			sig, err := SignWithOptions(prv, []byte(v.Message), v.Hash, &SignOptions{Encoding: P1363, Deterministic: true})
+			assert.NoError(t, err)
+			assert.Equal(t, v.R+v.S, strings.ToUpper(hex.EncodeToString(sig)))
+			assert.True(t, VerifyWithOptions(&prv.PublicKey, []byte(v.Message), sig, v.Hash, &SignOptions{Encoding: P1363}))
+
+			again, err := SignWithOptions(prv, []byte(v.Message), v.Hash, &SignOptions{Encoding: P1363, Deterministic: true})
+			assert.NoError(t, err)
+			assert.Equal(t, sig, again)
+		})
+	}
+}
+
+func TestLowS(t *testing.T) {
+	prv := keyFromHex(elliptic.P256(), "C9AFA9D845BA75166B5C215767B1D6934E50C3DB36E89B127B8A622B120F6721")
+	src := []byte("sample")
+
+	// s of the RFC 6979 "sample" signature is high
+	high, err := SignWithOptions(prv, src, sha256.New, &SignOptions{Deterministic: true})
+	assert.NoError(t, err)
+	assert.True(t, VerifyWithOptions(&prv.PublicKey, src, high, sha256.New, nil))
+	assert.False(t, VerifyWithOptions(&prv.PublicKey, src, high, sha256.New, &SignOptions{LowS: true}))
+
+	low, err := SignWithOptions(prv, src, sha256.New, &SignOptions{Deterministic: true, LowS: true})
+	assert.NoError(t, err)
+	assert.NotEqual(t, high, low)
+	assert.True(t, VerifyWithOptions(&prv.PublicKey, src, low, sha256.New, &SignOptions{LowS: true}))
+
+	normalized, err := NormalizeSignature(elliptic.P256(), high, ASN1)
+	assert.NoError(t, err)
+	assert.Equal(t, low, normalized)
+
+	for _, curve := range []elliptic.Curve{elliptic.P256(), elliptic.P384(), elliptic.P521()} {
+		prv, err := GenerateKey(rand.Reader, curve, nil)
+		assert.NoError(t, err)
+		for i := 0; i < 8; i++ {
+			sig, err := SignWithOptions(prv, src, sha512.New, &SignOptions{Encoding: P1363, LowS: true})
+			assert.NoError(t, err)
+			assert.True(t, VerifyWithOptions(&prv.PublicKey, src, sig, sha512.New, &SignOptions{Encoding: P1363, LowS: true}))
+		}
+	}
+}
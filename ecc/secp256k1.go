@@ -0,0 +1,79 @@
+package ecc
+
+import (
+	"crypto/elliptic"
+	"fmt"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+)
+
+var ErrNotSecp256k1 = fmt.Errorf("ecc: key is not a secp256k1 key")
+
+// CompactSignatureSize is the size of a recoverable r||s||v signature.
+const CompactSignatureSize = 65
+
+// S256 returns the secp256k1 curve used by Bitcoin and Ethereum. It can be
+// passed to GenerateKey, Sign, Verify, Encrypt and the other functions of
+// this package like the NIST curves.
+func S256() elliptic.Curve {
+	return secp256k1.S256()
+}
+
+// SignCompact signs a 32 byte digest with a secp256k1 key and returns a
+// 65 byte r||s||v signature, where v is the recovery id 0 or 1 (add 27 for
+// Ethereum's legacy v). The nonce is derived with RFC 6979 and s is low.
+func SignCompact(p *PrivateKey, digest []byte) ([]byte, error) {
+	if p.Curve != S256() {
+		return nil, ErrNotSecp256k1
+	}
+	if len(digest) != 32 {
+		return nil, fmt.Errorf("ecc: digest must be 32 bytes, got %d", len(digest))
+	}
+	key := secp256k1.PrivKeyFromBytes(p.D.FillBytes(make([]byte, 32)))
+	defer key.Zero()
+
+	// ecdsa.SignCompact returns 27+v (+4 for compressed keys) || r || s.
+	sig := ecdsa.SignCompact(key, digest, false)
+	compact := make([]byte, CompactSignatureSize)
+	copy(compact, sig[1:])
+	compact[64] = sig[0] - 27
+	return compact, nil
+}
+
+// RecoverCompact recovers the secp256k1 public key from a 65 byte r||s||v
+// signature and the 32 byte digest it signs. v may be 0, 1, 27 or 28.
+func RecoverCompact(digest, sig []byte) (*PublicKey, error) {
+	if len(sig) != CompactSignatureSize {
+		return nil, ErrInvalidSignature
+	}
+	v := sig[64]
+	if v >= 27 {
+		v -= 27
+	}
+	if v > 1 {
+		return nil, ErrInvalidSignature
+	}
+	bitcoinSig := make([]byte, CompactSignatureSize)
+	bitcoinSig[0] = 27 + v
+	copy(bitcoinSig[1:], sig[:64])
+
+	pub, _, err := ecdsa.RecoverCompact(bitcoinSig, digest)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidSignature, err)
+	}
+	return ImportECDSAPublic(pub.ToECDSA()), nil
+}
+
+// VerifyCompact verifies a 65 byte r||s||v signature of digest by
+// recovering the public key and comparing it with p.
+func VerifyCompact(p *PublicKey, digest, sig []byte) bool {
+	if p.Curve != S256() {
+		return false
+	}
+	pub, err := RecoverCompact(digest, sig)
+	if err != nil {
+		return false
+	}
+	return pub.X.Cmp(p.X) == 0 && pub.Y.Cmp(p.Y) == 0
+}
@@ -0,0 +1,106 @@
+package ecc
+
+import (
+	"bytes"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"log"
+	"testing"
+)
+
+// test vector of go-ethereum's crypto package
+const (
+	secp256k1PublicKey = "04e32df42865e97135acfb65f3bae71bdc86f4d49150ad6a440b6f15878109880a0a2b2667f7e725ceea70c673093bf67663e0312623c8e091b13cf2c0f11ef652"
+	secp256k1Digest    = "ce0677bb30baa8cf067c88db9811f4333d131bf8bcf12fe7065d211dce971008"
+	secp256k1Signature = "90f27b8b488db00b00606796d2987f6a5f59ae62ea05effe84fef5b8b0e549984a691139ad57a3f0b906637673aa2f63d1f55cb1a69199d4009eea23ceaddc9301"
+)
+
+func ExampleRecoverCompact() {
+	digest, _ := hex.DecodeString(secp256k1Digest)
+	sig, _ := hex.DecodeString(secp256k1Signature)
+	pub, err := RecoverCompact(digest, sig)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("%x\n", pub.X)
+	// Output: e32df42865e97135acfb65f3bae71bdc86f4d49150ad6a440b6f15878109880a
+}
+
+func TestRecoverCompact(t *testing.T) {
+	digest, _ := hex.DecodeString(secp256k1Digest)
+	sig, _ := hex.DecodeString(secp256k1Signature)
+
+	pub, err := RecoverCompact(digest, sig)
+	assert.NoError(t, err)
+	assert.Equal(t, secp256k1PublicKey, fmt.Sprintf("04%064x%064x", pub.X, pub.Y))
+	assert.True(t, VerifyCompact(pub, digest, sig))
+
+	// Ethereum legacy v
+	sig[64] += 27
+	assert.True(t, VerifyCompact(pub, digest, sig))
+	sig[64] = 2
+	_, err = RecoverCompact(digest, sig)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+	_, err = RecoverCompact(digest, sig[:64])
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+}
+
+func TestSignCompact(t *testing.T) {
+	prv, err := GenerateKey(rand.Reader, S256(), nil)
+	assert.NoError(t, err)
+	digest := sha256.Sum256([]byte("trumanwong"))
+
+	sig, err := SignCompact(prv, digest[:])
+	assert.NoError(t, err)
+	assert.Len(t, sig, CompactSignatureSize)
+	assert.True(t, VerifyCompact(&prv.PublicKey, digest[:], sig))
+
+	pub, err := RecoverCompact(digest[:], sig)
+	assert.NoError(t, err)
+	assert.Equal(t, prv.PublicKey.X, pub.X)
+	assert.Equal(t, prv.PublicKey.Y, pub.Y)
+
+	other, err := GenerateKey(rand.Reader, S256(), nil)
+	assert.NoError(t, err)
+	assert.False(t, VerifyCompact(&other.PublicKey, digest[:], sig))
+
+	_, err = SignCompact(prv, digest[:16])
+	assert.Error(t, err)
+	p256, err := GenerateKey(rand.Reader, elliptic.P256(), nil)
+	assert.NoError(t, err)
+	_, err = SignCompact(p256, digest[:])
+	assert.ErrorIs(t, err, ErrNotSecp256k1)
+}
+
+func TestSecp256k1(t *testing.T) {
+	src := []byte("trumanwong")
+	prv, err := GenerateKey(rand.Reader, S256(), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, ECIES_AES128_SHA256, prv.Params)
+
+	rb, sb, err := Sign(prv, src, sha256.New())
+	assert.NoError(t, err)
+	assert.True(t, Verify(&prv.PublicKey, src, rb, sb, sha256.New()))
+
+	sig, err := SignWithOptions(prv, src, sha256.New, &SignOptions{Deterministic: true, LowS: true})
+	assert.NoError(t, err)
+	assert.True(t, VerifyWithOptions(&prv.PublicKey, src, sig, sha256.New, &SignOptions{LowS: true}))
+
+	// RFC 6979 signatures match the recoverable ones
+	digest := sha256.Sum256(src)
+	compact, err := SignCompact(prv, digest[:])
+	assert.NoError(t, err)
+	p1363, err := ASN1ToP1363(S256(), sig)
+	assert.NoError(t, err)
+	assert.True(t, bytes.Equal(p1363, compact[:64]))
+
+	ct, err := Encrypt(rand.Reader, &prv.PublicKey, src, nil, nil)
+	assert.NoError(t, err)
+	pt, err := prv.Decrypt(ct, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, src, pt)
+}
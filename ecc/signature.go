@@ -0,0 +1,193 @@
+package ecc
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/asn1"
+	"fmt"
+	"hash"
+	"math/big"
+)
+
+var ErrInvalidSignature = fmt.Errorf("ecc: invalid signature")
+
+// ecdsaSignature is the ASN.1 structure of an ECDSA signature.
+type ecdsaSignature struct {
+	R, S *big.Int
+}
+
+// SignASN1 signs the hash of src and returns an ASN.1 DER signature, the
+// encoding OpenSSL and Java produce.
+func SignASN1(p *PrivateKey, src []byte, h hash.Hash) ([]byte, error) {
+	h.Write(src)
+	r, s, err := ecdsa.Sign(rand.Reader, p.ExportECDSA(), h.Sum(nil))
+	if err != nil {
+		return nil, err
+	}
+	return marshalASN1(r, s)
+}
+
+// VerifyASN1 verifies an ASN.1 DER signature of the hash of src.
+func VerifyASN1(p *PublicKey, src, sig []byte, h hash.Hash) bool {
+	r, s, err := parseASN1(sig)
+	if err != nil {
+		return false
+	}
+	h.Write(src)
+	return ecdsa.Verify(p.ExportECDSA(), h.Sum(nil), r, s)
+}
+
+// SignP1363 signs the hash of src and returns a fixed width IEEE P1363
+// r||s signature, the encoding JWS (ES256) and WebCrypto use.
+func SignP1363(p *PrivateKey, src []byte, h hash.Hash) ([]byte, error) {
+	h.Write(src)
+	r, s, err := ecdsa.Sign(rand.Reader, p.ExportECDSA(), h.Sum(nil))
+	if err != nil {
+		return nil, err
+	}
+	return marshalP1363(p.Curve, r, s), nil
+}
+
+// VerifyP1363 verifies an IEEE P1363 r||s signature of the hash of src.
+func VerifyP1363(p *PublicKey, src, sig []byte, h hash.Hash) bool {
+	r, s, err := parseP1363(p.Curve, sig)
+	if err != nil {
+		return false
+	}
+	h.Write(src)
+	return ecdsa.Verify(p.ExportECDSA(), h.Sum(nil), r, s)
+}
+
+// ASN1ToP1363 converts an ASN.1 DER signature to IEEE P1363 r||s for curve.
+func ASN1ToP1363(curve elliptic.Curve, sig []byte) ([]byte, error) {
+	r, s, err := parseASN1(sig)
+	if err != nil {
+		return nil, err
+	}
+	if !inRange(curve, r, s) {
+		return nil, ErrInvalidSignature
+	}
+	return marshalP1363(curve, r, s), nil
+}
+
+// P1363ToASN1 converts an IEEE P1363 r||s signature for curve to ASN.1 DER.
+func P1363ToASN1(curve elliptic.Curve, sig []byte) ([]byte, error) {
+	r, s, err := parseP1363(curve, sig)
+	if err != nil {
+		return nil, err
+	}
+	return marshalASN1(r, s)
+}
+
+// TextToASN1 converts the decimal text r and s returned by Sign to an
+// ASN.1 DER signature.
+func TextToASN1(rb, sb []byte) ([]byte, error) {
+	r, s, err := parseText(rb, sb)
+	if err != nil {
+		return nil, err
+	}
+	return marshalASN1(r, s)
+}
+
+// TextToP1363 converts the decimal text r and s returned by Sign to an
+// IEEE P1363 r||s signature for curve.
+func TextToP1363(curve elliptic.Curve, rb, sb []byte) ([]byte, error) {
+	r, s, err := parseText(rb, sb)
+	if err != nil {
+		return nil, err
+	}
+	if !inRange(curve, r, s) {
+		return nil, ErrInvalidSignature
+	}
+	return marshalP1363(curve, r, s), nil
+}
+
+// ASN1ToText converts an ASN.1 DER signature to the decimal text r and s
+// accepted by Verify.
+func ASN1ToText(sig []byte) ([]byte, []byte, error) {
+	r, s, err := parseASN1(sig)
+	if err != nil {
+		return nil, nil, err
+	}
+	return marshalText(r, s)
+}
+
+// P1363ToText converts an IEEE P1363 r||s signature for curve to the
+// decimal text r and s accepted by Verify.
+func P1363ToText(curve elliptic.Curve, sig []byte) ([]byte, []byte, error) {
+	r, s, err := parseP1363(curve, sig)
+	if err != nil {
+		return nil, nil, err
+	}
+	return marshalText(r, s)
+}
+
+func marshalASN1(r, s *big.Int) ([]byte, error) {
+	return asn1.Marshal(ecdsaSignature{r, s})
+}
+
+func parseASN1(sig []byte) (*big.Int, *big.Int, error) {
+	var v ecdsaSignature
+	rest, err := asn1.Unmarshal(sig, &v)
+	if err != nil || len(rest) != 0 {
+		return nil, nil, ErrInvalidSignature
+	}
+	if v.R.Sign() <= 0 || v.S.Sign() <= 0 {
+		return nil, nil, ErrInvalidSignature
+	}
+	return v.R, v.S, nil
+}
+
+func marshalP1363(curve elliptic.Curve, r, s *big.Int) []byte {
+	size := (curve.Params().N.BitLen() + 7) / 8
+	sig := make([]byte, 2*size)
+	r.FillBytes(sig[:size])
+	s.FillBytes(sig[size:])
+	return sig
+}
+
+func parseP1363(curve elliptic.Curve, sig []byte) (*big.Int, *big.Int, error) {
+	size := (curve.Params().N.BitLen() + 7) / 8
+	if len(sig) != 2*size {
+		return nil, nil, ErrInvalidSignature
+	}
+	r := new(big.Int).SetBytes(sig[:size])
+	s := new(big.Int).SetBytes(sig[size:])
+	if !inRange(curve, r, s) {
+		return nil, nil, ErrInvalidSignature
+	}
+	return r, s, nil
+}
+
+// inRange reports whether r and s are in [1, N-1] for curve.
+func inRange(curve elliptic.Curve, r, s *big.Int) bool {
+	n := curve.Params().N
+	return r.Sign() > 0 && s.Sign() > 0 && r.Cmp(n) < 0 && s.Cmp(n) < 0
+}
+
+func marshalText(r, s *big.Int) ([]byte, []byte, error) {
+	rb, err := r.MarshalText()
+	if err != nil {
+		return nil, nil, err
+	}
+	sb, err := s.MarshalText()
+	if err != nil {
+		return nil, nil, err
+	}
+	return rb, sb, nil
+}
+
+func parseText(rb, sb []byte) (*big.Int, *big.Int, error) {
+	var r, s big.Int
+	if err := r.UnmarshalText(rb); err != nil {
+		return nil, nil, ErrInvalidSignature
+	}
+	if err := s.UnmarshalText(sb); err != nil {
+		return nil, nil, ErrInvalidSignature
+	}
+	if r.Sign() <= 0 || s.Sign() <= 0 {
+		return nil, nil, ErrInvalidSignature
+	}
+	return &r, &s, nil
+}
@@ -0,0 +1,102 @@
+package ecc
+
+import (
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"log"
+	"math/big"
+	"testing"
+)
+
+// openssl dgst -sha256 -sign of "trumanwong" with opensslPrivateKey
+const opensslSignature = "MEQCIHVU5QafrsbSO0Z/zMPhhulPqP+a1IfEBLt1cXDS0+FzAiAenjj60d8fTKjRP5SF3JjJSxK/K+R2L/gk+lzljOu1tA=="
+
+func ExampleVerifyASN1() {
+	pub, err := ParsePublicKey([]byte(opensslPublicKey))
+	if err != nil {
+		log.Fatal(err)
+	}
+	sig, err := base64.StdEncoding.DecodeString(opensslSignature)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(VerifyASN1(pub, []byte("trumanwong"), sig, sha256.New()))
+	// Output: true
+}
+
+func TestSignASN1(t *testing.T) {
+	for _, curve := range []elliptic.Curve{elliptic.P256(), elliptic.P384(), elliptic.P521()} {
+		t.Run(curve.Params().Name, func(t *testing.T) {
+			prv, err := GenerateKey(rand.Reader, curve, nil)
+			assert.NoError(t, err)
+			src := []byte("trumanwong")
+
+			der, err := SignASN1(prv, src, sha512.New())
+			assert.NoError(t, err)
+			assert.True(t, VerifyASN1(&prv.PublicKey, src, der, sha512.New()))
+			assert.False(t, VerifyASN1(&prv.PublicKey, []byte("TrumanWong"), der, sha512.New()))
+
+			p1363, err := SignP1363(prv, src, sha512.New())
+			assert.NoError(t, err)
+			assert.Len(t, p1363, 2*((curve.Params().BitSize+7)/8))
+			assert.True(t, VerifyP1363(&prv.PublicKey, src, p1363, sha512.New()))
+			assert.False(t, VerifyP1363(&prv.PublicKey, src, p1363[1:], sha512.New()))
+
+			// convert every encoding to the others and verify
+			rb, sb, err := ASN1ToText(der)
+			assert.NoError(t, err)
+			assert.True(t, Verify(&prv.PublicKey, src, rb, sb, sha512.New()))
+			converted, err := TextToP1363(curve, rb, sb)
+			assert.NoError(t, err)
+			assert.True(t, VerifyP1363(&prv.PublicKey, src, converted, sha512.New()))
+			converted, err = P1363ToASN1(curve, converted)
+			assert.NoError(t, err)
+			assert.Equal(t, der, converted)
+
+			rb, sb, err = Sign(prv, src, sha512.New())
+			assert.NoError(t, err)
+			converted, err = TextToASN1(rb, sb)
+			assert.NoError(t, err)
+			assert.True(t, VerifyASN1(&prv.PublicKey, src, converted, sha512.New()))
+			converted, err = ASN1ToP1363(curve, converted)
+			assert.NoError(t, err)
+			rb2, sb2, err := P1363ToText(curve, converted)
+			assert.NoError(t, err)
+			assert.Equal(t, rb, rb2)
+			assert.Equal(t, sb, sb2)
+		})
+	}
+}
+
+func TestParseSignature(t *testing.T) {
+	_, _, err := ASN1ToText([]byte("trumanwong"))
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+	_, err = TextToASN1([]byte("-1"), []byte("1"))
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+	_, err = P1363ToASN1(elliptic.P256(), make([]byte, 64))
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+	// r or s not below the order of the curve
+	n := elliptic.P256().Params().N
+	for _, rs := range [][2]*big.Int{{n, big.NewInt(1)}, {big.NewInt(1), n}, {big.NewInt(1), new(big.Int).Add(n, big.NewInt(1))}} {
+		sig := make([]byte, 64)
+		rs[0].FillBytes(sig[:32])
+		rs[1].FillBytes(sig[32:])
+		_, err = P1363ToASN1(elliptic.P256(), sig)
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+		_, _, err = P1363ToText(elliptic.P256(), sig)
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(opensslSignature)
+	assert.NoError(t, err)
+	_, err = ASN1ToP1363(elliptic.P256(), append(sig, 0))
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+	p1363, err := ASN1ToP1363(elliptic.P256(), sig)
+	assert.NoError(t, err)
+	assert.Len(t, p1363, 64)
+}
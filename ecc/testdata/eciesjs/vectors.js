@@ -0,0 +1,8 @@
+// Generates the secp256k1 ECIESJS vector of profile_test.go with eciesjs
+// itself, in its default configuration: cd ecc/testdata/eciesjs && npm
+// install && node vectors.js
+import { PrivateKey, encrypt } from 'eciesjs';
+
+const sk = PrivateKey.fromHex('2c7d4e0b6c1dbf0e3aa3cd2d9d1c2b8d4f6a0b9e1c7d3f5a8b2e4c6d8f0a1b3c');
+const ct = encrypt(sk.publicKey.toHex(), Buffer.from('trumanwong'));
+console.log('secp256k1 eciesjs', Buffer.from(ct).toString('hex'));
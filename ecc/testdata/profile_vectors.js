@@ -0,0 +1,47 @@
+// Generates the ECIESJS, AESGCM and ChaCha20Poly1305 vectors of
+// profile_test.go with Node.js 20 crypto (OpenSSL 3.0), independently of the
+// Go code, using random ephemeral keys and nonces:
+// node ecc/testdata/profile_vectors.js
+const crypto = require('crypto');
+const d = BigInt('0x2c7d4e0b6c1dbf0e3aa3cd2d9d1c2b8d4f6a0b9e1c7d3f5a8b2e4c6d8f0a1b3c');
+const curves = {
+  secp256k1: BigInt('0xfffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141'),
+  prime256v1: BigInt('0xffffffff00000000ffffffffffffffffbce6faada7179e84f3b9cac2fc632551'),
+};
+const hex32 = (n) => Buffer.from(n.toString(16).padStart(64, '0'), 'hex');
+const msg = Buffer.from('trumanwong');
+const s1 = Buffer.from('s1'), s2 = Buffer.from('s2');
+
+for (const [name, n] of Object.entries(curves)) {
+  const recipient = crypto.createECDH(name); recipient.setPrivateKey(hex32(d));
+  const P = recipient.getPublicKey();
+  const out = {};
+  for (const profile of ['eciesjs', 'aes-gcm', 'chacha20-poly1305']) {
+    const eph = crypto.createECDH(name); eph.generateKeys();
+    const k = BigInt('0x' + eph.getPrivateKey('hex'));
+    const z = eph.computeSecret(P); // x coordinate
+    // full shared point k*d*G, for eciesjs
+    const sp = crypto.createECDH(name); sp.setPrivateKey(hex32((k * d) % n));
+    const S = sp.getPublicKey();
+    if (!S.subarray(1, 33).equals(z)) throw new Error('shared point mismatch');
+    let ct;
+    if (profile === 'eciesjs') {
+      const R = eph.getPublicKey();
+      const key = Buffer.from(crypto.hkdfSync('sha256', Buffer.concat([R, S]), Buffer.alloc(0), Buffer.alloc(0), 32));
+      const nonce = crypto.randomBytes(16);
+      const c = crypto.createCipheriv('aes-256-gcm', key, nonce);
+      const em = Buffer.concat([c.update(msg), c.final()]);
+      ct = Buffer.concat([R, nonce, c.getAuthTag(), em]);
+    } else {
+      const R = eph.getPublicKey(null, 'compressed');
+      const key = Buffer.from(crypto.hkdfSync('sha256', z, R, s1, 32));
+      const nonce = crypto.randomBytes(12);
+      const alg = profile === 'aes-gcm' ? 'aes-256-gcm' : 'chacha20-poly1305';
+      const c = crypto.createCipheriv(alg, key, nonce, { authTagLength: 16 });
+      c.setAAD(s2);
+      const em = Buffer.concat([c.update(msg), c.final()]);
+      ct = Buffer.concat([R, nonce, em, c.getAuthTag()]);
+    }
+    console.log(name, profile, ct.toString('hex'));
+  }
+}
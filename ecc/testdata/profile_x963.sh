@@ -0,0 +1,27 @@
+#!/bin/sh
+# Generates the X963 vectors of profile_test.go with the OpenSSL 3.0.17
+# command line tool alone: ECDH with pkeyutl -derive, the KDF with
+# OpenSSL's X963KDF, AES-128-CTR with enc and HMAC-SHA256 with dgst. The
+# ephemeral keys are random. Run: sh ecc/testdata/profile_x963.sh
+set -e
+d=2c7d4e0b6c1dbf0e3aa3cd2d9d1c2b8d4f6a0b9e1c7d3f5a8b2e4c6d8f0a1b3c
+tmp=$(mktemp -d)
+trap 'rm -rf "$tmp"' EXIT
+hex() { xxd -p | tr -d '\n'; }
+
+# name and the SEC1 ECPrivateKey DER around d
+for curve in "secp256k1 302e0201010420${d}a00706052b8104000a" \
+	"prime256v1 30310201010420${d}a00a06082a8648ce3d030107"; do
+	set -- $curve
+	echo "$2" | xxd -r -p | openssl ec -inform DER -pubout -out "$tmp/rcpt.pem" 2>/dev/null
+	openssl ecparam -name "$1" -genkey -noout -out "$tmp/eph.pem"
+	z=$(openssl pkeyutl -derive -inkey "$tmp/eph.pem" -peerkey "$tmp/rcpt.pem" | hex)
+	R=$(openssl ec -in "$tmp/eph.pem" -pubout -conv_form compressed -outform DER 2>/dev/null | tail -c 33 | hex)
+	k=$(openssl kdf -keylen 48 -kdfopt digest:SHA256 -kdfopt hexsecret:"$z" \
+		-kdfopt hexinfo:"$(printf s1 | hex)" X963KDF | tr -d ':' | tr 'A-F' 'a-f')
+	ke=$(echo "$k" | cut -c1-32)
+	km=$(echo "$k" | cut -c33-96)
+	printf trumanwong | openssl enc -aes-128-ctr -K "$ke" -iv 00000000000000000000000000000000 -out "$tmp/em"
+	tag=$( (cat "$tmp/em"; printf s2) | openssl dgst -sha256 -mac HMAC -macopt hexkey:"$km" -binary | hex)
+	echo "$1 x963 $R$(hex < "$tmp/em")$tag"
+done
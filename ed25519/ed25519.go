@@ -0,0 +1,235 @@
+package ed25519
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"io"
+)
+
+type variant string
+
+// The Ed25519 variants of RFC 8032, section 5.1.
+const (
+	Pure variant = "ed25519"
+	Ctx  variant = "ed25519ctx"
+	Ph   variant = "ed25519ph"
+)
+
+// SignOptions selects the variant used by SignWithOptions and
+// VerifyWithOptions. Context is required by Ctx, optional for Ph and must be
+// empty for Pure. Nil options mean Pure.
+type SignOptions struct {
+	Variant variant
+	Context string
+}
+
+// GenerateKeyPair Generate ed25519 key pair, return the PKCS8 private key
+// pem and the PKIX public key pem.
+func GenerateKeyPair() (privateKey, publicKey []byte, err error) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	privateKey, err = marshalPrivateKey(priv)
+	if err != nil {
+		return nil, nil, err
+	}
+	publicKey, err = marshalPublicKey(pub)
+	if err != nil {
+		return nil, nil, err
+	}
+	return privateKey, publicKey, nil
+}
+
+// NewKeyFromSeed return the PKCS8 private key pem of the 32 byte RFC 8032
+// private key seed.
+func NewKeyFromSeed(seed []byte) ([]byte, error) {
+	if len(seed) != ed25519.SeedSize {
+		return nil, errors.New("invalid seed size")
+	}
+	return marshalPrivateKey(ed25519.NewKeyFromSeed(seed))
+}
+
+// ExtractPublicKey return the PKIX public key pem of the private key.
+func ExtractPublicKey(privateKey []byte) ([]byte, error) {
+	priv, err := parsePrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	return marshalPublicKey(priv.Public().(ed25519.PublicKey))
+}
+
+// ParsePrivateKey parse a PKCS8 private key pem, for use with
+// crypto/ed25519 or other packages such as jwt.
+func ParsePrivateKey(privateKey []byte) (ed25519.PrivateKey, error) {
+	return parsePrivateKey(privateKey)
+}
+
+// ParsePublicKey parse a PKIX or X.509 certificate public key pem, for use
+// with crypto/ed25519 or other packages such as jwt.
+func ParsePublicKey(publicKey []byte) (ed25519.PublicKey, error) {
+	return parsePublicKey(publicKey)
+}
+
+// Sign ed25519 sign, the private key is a PKCS8 pem.
+func Sign(src, privateKey []byte) ([]byte, error) {
+	return SignWithOptions(src, privateKey, nil)
+}
+
+// Verify ed25519 verify, the public key is a PKIX pem.
+func Verify(src, sign, publicKey []byte) error {
+	return VerifyWithOptions(src, sign, publicKey, nil)
+}
+
+// SignWithOptions ed25519 sign with the variant in opts. For Ph, src is the
+// message, which is hashed with SHA-512 here.
+func SignWithOptions(src, privateKey []byte, opts *SignOptions) ([]byte, error) {
+	priv, err := parsePrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	msg, options, err := prepare(src, opts)
+	if err != nil {
+		return nil, err
+	}
+	return priv.Sign(nil, msg, options)
+}
+
+// VerifyWithOptions ed25519 verify with the variant in opts.
+func VerifyWithOptions(src, sign, publicKey []byte, opts *SignOptions) error {
+	pub, err := parsePublicKey(publicKey)
+	if err != nil {
+		return err
+	}
+	msg, options, err := prepare(src, opts)
+	if err != nil {
+		return err
+	}
+	return ed25519.VerifyWithOptions(pub, msg, sign, options)
+}
+
+// SignReader ed25519ph sign the content read from r, which is hashed as it
+// is read so large files don't need to be held in memory.
+func SignReader(r io.Reader, privateKey []byte, context string) ([]byte, error) {
+	priv, err := parsePrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	digest, err := hashReader(r)
+	if err != nil {
+		return nil, err
+	}
+	return priv.Sign(nil, digest, &ed25519.Options{Hash: crypto.SHA512, Context: context})
+}
+
+// VerifyReader ed25519ph verify the content read from r.
+func VerifyReader(r io.Reader, sign, publicKey []byte, context string) error {
+	pub, err := parsePublicKey(publicKey)
+	if err != nil {
+		return err
+	}
+	digest, err := hashReader(r)
+	if err != nil {
+		return err
+	}
+	return ed25519.VerifyWithOptions(pub, digest, sign, &ed25519.Options{Hash: crypto.SHA512, Context: context})
+}
+
+// prepare returns the message and the crypto/ed25519 options of a variant.
+func prepare(src []byte, opts *SignOptions) ([]byte, *ed25519.Options, error) {
+	if opts == nil {
+		opts = &SignOptions{Variant: Pure}
+	}
+	switch opts.Variant {
+	case "", Pure:
+		if opts.Context != "" {
+			return nil, nil, errors.New("ed25519 does not support a context, use ed25519ctx")
+		}
+		return src, &ed25519.Options{}, nil
+	case Ctx:
+		if opts.Context == "" {
+			return nil, nil, errors.New("ed25519ctx requires a context")
+		}
+		return src, &ed25519.Options{Context: opts.Context}, nil
+	case Ph:
+		digest := sha512.Sum512(src)
+		return digest[:], &ed25519.Options{Hash: crypto.SHA512, Context: opts.Context}, nil
+	}
+	return nil, nil, errors.New("unsupported ed25519 variant")
+}
+
+func hashReader(r io.Reader) ([]byte, error) {
+	h := sha512.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
+
+func marshalPrivateKey(priv ed25519.PrivateKey) ([]byte, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+func marshalPublicKey(pub ed25519.PublicKey) ([]byte, error) {
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), nil
+}
+
+// parse private key from a PKCS8 pem. return ed25519.PrivateKey
+func parsePrivateKey(privateKey []byte) (ed25519.PrivateKey, error) {
+	block, _ := pem.Decode(privateKey)
+	if block == nil || block.Type != "PRIVATE KEY" {
+		return nil, errors.New("key is invalid format")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	priv, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, errors.New("key is invalid private key")
+	}
+	return priv, nil
+}
+
+// parse public key from a PKIX pem or an X.509 certificate. return
+// ed25519.PublicKey
+func parsePublicKey(publicKey []byte) (ed25519.PublicKey, error) {
+	block, _ := pem.Decode(publicKey)
+	if block == nil {
+		return nil, errors.New("key is invalid format")
+	}
+	var key crypto.PublicKey
+	switch block.Type {
+	case "PUBLIC KEY":
+		var err error
+		if key, err = x509.ParsePKIXPublicKey(block.Bytes); err != nil {
+			return nil, err
+		}
+	case "CERTIFICATE":
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		key = cert.PublicKey
+	default:
+		return nil, errors.New("key is invalid format")
+	}
+	pub, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, errors.New("key is invalid public key")
+	}
+	return pub, nil
+}
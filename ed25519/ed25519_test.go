@@ -0,0 +1,117 @@
+package ed25519
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func ExampleSign() {
+	priKey, pubKey, err := GenerateKeyPair()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	src := []byte("trumanwong")
+	sign, err := Sign(src, priKey)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = Verify(src, sign, pubKey)
+	fmt.Println(len(sign), err)
+	// Output: 64 <nil>
+}
+
+// test vectors of RFC 8032, section 7.1 to 7.3
+var rfc8032Tests = []struct {
+	name, seed, msg, sig string
+	opts                 *SignOptions
+}{
+	{
+		name: "ed25519",
+		seed: "9d61b19deffd5a60ba844af492ec2cc44449c5697b326919703bac031cae7f60",
+		msg:  "",
+		sig:  "e5564300c360ac729086e2cc806e828a84877f1eb8e5d974d873e065224901555fb8821590a33bacc61e39701cf9b46bd25bf5f0595bbe24655141438e7a100b",
+		opts: nil,
+	},
+	{
+		name: "ed25519ctx",
+		seed: "0305334e381af78f141cb666f6199f57bc3495335a256a95bd2a55bf546663f6",
+		msg:  "f726936d19c800494e3fdaff20b276a8",
+		sig:  "55a4cc2f70a54e04288c5f4cd1e45a7bb520b36292911876cada7323198dd87a8b36950b95130022907a7fb7c4e9b2d5f6cca685a587b4b21f4b888e4e7edb0d",
+		opts: &SignOptions{Variant: Ctx, Context: "foo"},
+	},
+	{
+		name: "ed25519ph",
+		seed: "833fe62409237b9d62ec77587520911e9a759cec1d19755b7da901b96dca3d42",
+		msg:  "616263",
+		sig:  "98a70222f0b8121aa9d30f813d683f809e462b469c7ff87639499bb94e6dae4131f85042463c2a355a2003d062adf5aaa10b8c61e636062aaad11c2a26083406",
+		opts: &SignOptions{Variant: Ph},
+	},
+}
+
+func TestRFC8032(t *testing.T) {
+	for _, tt := range rfc8032Tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seed, _ := hex.DecodeString(tt.seed)
+			msg, _ := hex.DecodeString(tt.msg)
+			priKey, err := NewKeyFromSeed(seed)
+			assert.NoError(t, err)
+			pubKey, err := ExtractPublicKey(priKey)
+			assert.NoError(t, err)
+
+			sign, err := SignWithOptions(msg, priKey, tt.opts)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.sig, hex.EncodeToString(sign))
+			assert.NoError(t, VerifyWithOptions(msg, sign, pubKey, tt.opts))
+			assert.Error(t, VerifyWithOptions([]byte("TrumanWong"), sign, pubKey, tt.opts))
+		})
+	}
+}
+
+func TestSignWithOptions(t *testing.T) {
+	priKey, pubKey, err := GenerateKeyPair()
+	assert.NoError(t, err)
+
+	src := []byte("trumanwong")
+	pure, err := Sign(src, priKey)
+	assert.NoError(t, err)
+	ctx, err := SignWithOptions(src, priKey, &SignOptions{Variant: Ctx, Context: "artifact"})
+	assert.NoError(t, err)
+	ph, err := SignWithOptions(src, priKey, &SignOptions{Variant: Ph})
+	assert.NoError(t, err)
+
+	// signatures are bound to their variant and context
+	assert.Error(t, VerifyWithOptions(src, pure, pubKey, &SignOptions{Variant: Ph}))
+	assert.Error(t, VerifyWithOptions(src, ctx, pubKey, &SignOptions{Variant: Ctx, Context: "release"}))
+	assert.Error(t, Verify(src, ph, pubKey))
+
+	_, err = SignWithOptions(src, priKey, &SignOptions{Variant: Ctx})
+	assert.Error(t, err)
+	_, err = SignWithOptions(src, priKey, &SignOptions{Context: "artifact"})
+	assert.Error(t, err)
+	_, err = SignWithOptions(src, priKey, &SignOptions{Variant: "ed448"})
+	assert.Error(t, err)
+	_, err = SignWithOptions(src, pubKey, nil)
+	assert.Error(t, err)
+	_, err = NewKeyFromSeed(src)
+	assert.Error(t, err)
+}
+
+func TestSignReader(t *testing.T) {
+	priKey, pubKey, err := GenerateKeyPair()
+	assert.NoError(t, err)
+
+	src := strings.Repeat("trumanwong", 10000)
+	sign, err := SignReader(strings.NewReader(src), priKey, "artifact")
+	assert.NoError(t, err)
+	assert.NoError(t, VerifyReader(strings.NewReader(src), sign, pubKey, "artifact"))
+	assert.NoError(t, VerifyWithOptions([]byte(src), sign, pubKey, &SignOptions{Variant: Ph, Context: "artifact"}))
+	assert.Error(t, VerifyReader(bytes.NewReader(nil), sign, pubKey, "artifact"))
+}
@@ -0,0 +1,69 @@
+package ed25519
+
+import (
+	"crypto/ecdh"
+	"crypto/ed25519"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/pem"
+
+	"filippo.io/edwards25519"
+)
+
+// ToX25519PrivateKey converts an ed25519 PKCS8 private key pem to an X25519
+// PKCS8 private key pem, as libsodium's crypto_sign_ed25519_sk_to_curve25519
+// does, so a signing key can also be used for ECDH and encryption.
+func ToX25519PrivateKey(privateKey []byte) ([]byte, error) {
+	priv, err := parsePrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	key, err := X25519PrivateKey(priv)
+	if err != nil {
+		return nil, err
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+// ToX25519PublicKey converts an ed25519 PKIX public key pem to an X25519
+// PKIX public key pem, as libsodium's crypto_sign_ed25519_pk_to_curve25519
+// does.
+func ToX25519PublicKey(publicKey []byte) ([]byte, error) {
+	pub, err := parsePublicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	key, err := X25519PublicKey(pub)
+	if err != nil {
+		return nil, err
+	}
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), nil
+}
+
+// X25519PrivateKey converts an ed25519 private key to the X25519 private
+// key with the same scalar, the clamped first half of SHA-512 of the seed.
+func X25519PrivateKey(priv ed25519.PrivateKey) (*ecdh.PrivateKey, error) {
+	h := sha512.Sum512(priv.Seed())
+	h[0] &= 248
+	h[31] &= 127
+	h[31] |= 64
+	return ecdh.X25519().NewPrivateKey(h[:32])
+}
+
+// X25519PublicKey converts an ed25519 public key to the X25519 public key
+// u = (1 + y) / (1 - y). Invalid points are rejected.
+func X25519PublicKey(pub ed25519.PublicKey) (*ecdh.PublicKey, error) {
+	p, err := new(edwards25519.Point).SetBytes(pub)
+	if err != nil {
+		return nil, err
+	}
+	return ecdh.X25519().NewPublicKey(p.BytesMontgomery())
+}
@@ -0,0 +1,87 @@
+package ed25519
+
+import (
+	"crypto/ecdh"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func ExampleToX25519PublicKey() {
+	_, pubKey, err := GenerateKeyPair()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	x25519Key, err := ToX25519PublicKey(pubKey)
+	if err != nil {
+		log.Fatal(err)
+	}
+	block, _ := pem.Decode(x25519Key)
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(key.(*ecdh.PublicKey).Curve() == ecdh.X25519())
+	// Output: true
+}
+
+func TestX25519(t *testing.T) {
+	pub1, priv1, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	pub2, priv2, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	xpriv1, err := X25519PrivateKey(priv1)
+	assert.NoError(t, err)
+	xpub1, err := X25519PublicKey(pub1)
+	assert.NoError(t, err)
+	assert.True(t, xpriv1.PublicKey().Equal(xpub1))
+
+	xpriv2, err := X25519PrivateKey(priv2)
+	assert.NoError(t, err)
+	xpub2, err := X25519PublicKey(pub2)
+	assert.NoError(t, err)
+
+	z1, err := xpriv1.ECDH(xpub2)
+	assert.NoError(t, err)
+	z2, err := xpriv2.ECDH(xpub1)
+	assert.NoError(t, err)
+	assert.Equal(t, z1, z2)
+
+	// y = 2 is not on the curve
+	invalid := make([]byte, ed25519.PublicKeySize)
+	invalid[0] = 2
+	_, err = X25519PublicKey(invalid)
+	assert.Error(t, err)
+}
+
+func TestToX25519(t *testing.T) {
+	priKey, pubKey, err := GenerateKeyPair()
+	assert.NoError(t, err)
+
+	xPriKey, err := ToX25519PrivateKey(priKey)
+	assert.NoError(t, err)
+	xPubKey, err := ToX25519PublicKey(pubKey)
+	assert.NoError(t, err)
+
+	block, _ := pem.Decode(xPriKey)
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	assert.NoError(t, err)
+	priv := key.(*ecdh.PrivateKey)
+	block, _ = pem.Decode(xPubKey)
+	key, err = x509.ParsePKIXPublicKey(block.Bytes)
+	assert.NoError(t, err)
+	assert.True(t, priv.PublicKey().Equal(key))
+
+	_, err = ToX25519PrivateKey(pubKey)
+	assert.Error(t, err)
+	_, err = ToX25519PublicKey(priKey)
+	assert.Error(t, err)
+}
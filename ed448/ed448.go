@@ -0,0 +1,225 @@
+package ed448
+
+import (
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+
+	"github.com/cloudflare/circl/sign/ed448"
+)
+
+type variant string
+
+// The Ed448 variants of RFC 8032, section 5.2.
+const (
+	Pure variant = "ed448"
+	Ph   variant = "ed448ph"
+)
+
+// SignOptions selects the variant used by SignWithOptions and
+// VerifyWithOptions. Unlike Ed25519, both variants take an optional Context
+// of at most 255 bytes. Nil options mean Pure without a context.
+type SignOptions struct {
+	Variant variant
+	Context string
+}
+
+// oidEd448 is the algorithm identifier of RFC 8410.
+var oidEd448 = asn1.ObjectIdentifier{1, 3, 101, 113}
+
+// GenerateKeyPair Generate ed448 key pair, return the PKCS8 private key pem
+// and the PKIX public key pem.
+func GenerateKeyPair() (privateKey, publicKey []byte, err error) {
+	pub, priv, err := ed448.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	privateKey, err = marshalPrivateKey(priv)
+	if err != nil {
+		return nil, nil, err
+	}
+	publicKey, err = marshalPublicKey(pub)
+	if err != nil {
+		return nil, nil, err
+	}
+	return privateKey, publicKey, nil
+}
+
+// NewKeyFromSeed return the PKCS8 private key pem of the 57 byte RFC 8032
+// private key seed.
+func NewKeyFromSeed(seed []byte) ([]byte, error) {
+	if len(seed) != ed448.SeedSize {
+		return nil, errors.New("invalid seed size")
+	}
+	return marshalPrivateKey(ed448.NewKeyFromSeed(seed))
+}
+
+// ExtractPublicKey return the PKIX public key pem of the private key.
+func ExtractPublicKey(privateKey []byte) ([]byte, error) {
+	priv, err := parsePrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	return marshalPublicKey(priv.Public().(ed448.PublicKey))
+}
+
+// ParsePrivateKey parse a PKCS8 private key pem, for use with
+// github.com/cloudflare/circl/sign/ed448.
+func ParsePrivateKey(privateKey []byte) (ed448.PrivateKey, error) {
+	return parsePrivateKey(privateKey)
+}
+
+// ParsePublicKey parse a PKIX or X.509 certificate public key pem, for use
+// with github.com/cloudflare/circl/sign/ed448.
+func ParsePublicKey(publicKey []byte) (ed448.PublicKey, error) {
+	return parsePublicKey(publicKey)
+}
+
+// Sign ed448 sign, the private key is a PKCS8 pem.
+func Sign(src, privateKey []byte) ([]byte, error) {
+	return SignWithOptions(src, privateKey, nil)
+}
+
+// Verify ed448 verify, the public key is a PKIX pem.
+func Verify(src, sign, publicKey []byte) error {
+	return VerifyWithOptions(src, sign, publicKey, nil)
+}
+
+// SignWithOptions ed448 sign with the variant in opts. For Ph, src is the
+// message, which is hashed with SHAKE256 here.
+func SignWithOptions(src, privateKey []byte, opts *SignOptions) ([]byte, error) {
+	priv, err := parsePrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	if opts == nil {
+		opts = &SignOptions{Variant: Pure}
+	}
+	if len(opts.Context) > ed448.ContextMaxSize {
+		return nil, errors.New("ed448 context is longer than 255 bytes")
+	}
+	switch opts.Variant {
+	case "", Pure:
+		return ed448.Sign(priv, src, opts.Context), nil
+	case Ph:
+		return ed448.SignPh(priv, src, opts.Context), nil
+	}
+	return nil, errors.New("unsupported ed448 variant")
+}
+
+// VerifyWithOptions ed448 verify with the variant in opts.
+func VerifyWithOptions(src, sign, publicKey []byte, opts *SignOptions) error {
+	pub, err := parsePublicKey(publicKey)
+	if err != nil {
+		return err
+	}
+	if opts == nil {
+		opts = &SignOptions{Variant: Pure}
+	}
+	var ok bool
+	switch opts.Variant {
+	case "", Pure:
+		ok = ed448.Verify(pub, src, sign, opts.Context)
+	case Ph:
+		ok = ed448.VerifyPh(pub, src, sign, opts.Context)
+	default:
+		return errors.New("unsupported ed448 variant")
+	}
+	if !ok {
+		return errors.New("ed448: invalid signature")
+	}
+	return nil
+}
+
+// pkcs8 is the PrivateKeyInfo of RFC 5208, x509 does not know Ed448.
+type pkcs8 struct {
+	Version    int
+	Algo       pkix.AlgorithmIdentifier
+	PrivateKey []byte
+}
+
+// publicKeyInfo is the SubjectPublicKeyInfo of RFC 5280.
+type publicKeyInfo struct {
+	Algo      pkix.AlgorithmIdentifier
+	PublicKey asn1.BitString
+}
+
+func marshalPrivateKey(priv ed448.PrivateKey) ([]byte, error) {
+	// RFC 8410, section 7: the key is the seed in an OCTET STRING
+	seed, err := asn1.Marshal(priv.Seed())
+	if err != nil {
+		return nil, err
+	}
+	der, err := asn1.Marshal(pkcs8{Algo: pkix.AlgorithmIdentifier{Algorithm: oidEd448}, PrivateKey: seed})
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+func marshalPublicKey(pub ed448.PublicKey) ([]byte, error) {
+	der, err := asn1.Marshal(publicKeyInfo{
+		Algo:      pkix.AlgorithmIdentifier{Algorithm: oidEd448},
+		PublicKey: asn1.BitString{Bytes: pub, BitLength: 8 * len(pub)},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), nil
+}
+
+// parse private key from a PKCS8 pem. return ed448.PrivateKey
+func parsePrivateKey(privateKey []byte) (ed448.PrivateKey, error) {
+	block, _ := pem.Decode(privateKey)
+	if block == nil || block.Type != "PRIVATE KEY" {
+		return nil, errors.New("key is invalid format")
+	}
+	// PKCS#8 v2 adds an optional public key, which asn1 would reject as
+	// trailing data of the v1 structure.
+	var key pkcs8
+	if _, err := asn1.Unmarshal(block.Bytes, &key); err != nil {
+		return nil, err
+	}
+	if !key.Algo.Algorithm.Equal(oidEd448) {
+		return nil, errors.New("key is invalid private key")
+	}
+	var seed []byte
+	if rest, err := asn1.Unmarshal(key.PrivateKey, &seed); err != nil || len(rest) != 0 || len(seed) != ed448.SeedSize {
+		return nil, errors.New("key is invalid private key")
+	}
+	return ed448.NewKeyFromSeed(seed), nil
+}
+
+// parse public key from a PKIX pem or an X.509 certificate. return
+// ed448.PublicKey
+func parsePublicKey(publicKey []byte) (ed448.PublicKey, error) {
+	block, _ := pem.Decode(publicKey)
+	if block == nil {
+		return nil, errors.New("key is invalid format")
+	}
+	der := block.Bytes
+	switch block.Type {
+	case "PUBLIC KEY":
+	case "CERTIFICATE":
+		// x509 parses certificates of unknown key types, leaving the key
+		// in RawSubjectPublicKeyInfo
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		der = cert.RawSubjectPublicKeyInfo
+	default:
+		return nil, errors.New("key is invalid format")
+	}
+	var info publicKeyInfo
+	if rest, err := asn1.Unmarshal(der, &info); err != nil || len(rest) != 0 {
+		return nil, errors.New("key is invalid format")
+	}
+	if !info.Algo.Algorithm.Equal(oidEd448) || info.PublicKey.BitLength != 8*ed448.PublicKeySize {
+		return nil, errors.New("key is invalid public key")
+	}
+	return ed448.PublicKey(info.PublicKey.Bytes), nil
+}
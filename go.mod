@@ -5,8 +5,10 @@ go 1.23.0
 toolchain go1.24.2
 
 require (
+	filippo.io/edwards25519 v1.1.0
+	github.com/cloudflare/circl v1.6.1
+	github.com/decred/dcrd/dcrec/secp256k1/v4 v4.4.1
 	github.com/emmansun/gmsm v0.30.1
-	github.com/golang-jwt/jwt v3.2.2+incompatible
 	github.com/stretchr/testify v1.10.0
 	golang.org/x/crypto v0.38.0
 )
@@ -0,0 +1,124 @@
+package jwe
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/binary"
+	"fmt"
+)
+
+// encryptContent encrypts plaintext with the content encryption key and
+// authenticates aad, return the iv, ciphertext and tag.
+func encryptContent(enc ContentEncryption, cek, plaintext, aad []byte) (iv, ciphertext, tag []byte, err error) {
+	switch enc {
+	case A256GCM:
+		aead, err := newGCM(cek)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		iv = make([]byte, aead.NonceSize())
+		if _, err = rand.Read(iv); err != nil {
+			return nil, nil, nil, err
+		}
+		sealed := aead.Seal(nil, iv, plaintext, aad)
+		n := len(sealed) - aead.Overhead()
+		return iv, sealed[:n], sealed[n:], nil
+	case A128CBCHS256:
+		iv = make([]byte, aes.BlockSize)
+		if _, err = rand.Read(iv); err != nil {
+			return nil, nil, nil, err
+		}
+		ciphertext, tag, err = cbcHMACEncrypt(cek, iv, plaintext, aad)
+		return iv, ciphertext, tag, err
+	}
+	return nil, nil, nil, fmt.Errorf("%w: enc %q", ErrUnsupportedAlgorithm, enc)
+}
+
+// decryptContent checks the tag and decrypts the ciphertext.
+func decryptContent(enc ContentEncryption, cek, iv, ciphertext, tag, aad []byte) ([]byte, error) {
+	switch enc {
+	case A256GCM:
+		aead, err := newGCM(cek)
+		if err != nil {
+			return nil, err
+		}
+		if len(iv) != aead.NonceSize() || len(tag) != aead.Overhead() {
+			return nil, ErrDecryption
+		}
+		plaintext, err := aead.Open(nil, iv, append(ciphertext[:len(ciphertext):len(ciphertext)], tag...), aad)
+		if err != nil {
+			return nil, ErrDecryption
+		}
+		return plaintext, nil
+	case A128CBCHS256:
+		return cbcHMACDecrypt(cek, iv, ciphertext, tag, aad)
+	}
+	return nil, fmt.Errorf("%w: enc %q", ErrUnsupportedAlgorithm, enc)
+}
+
+func newGCM(cek []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// cbcHMACEncrypt is AES_128_CBC_HMAC_SHA_256 of RFC 7518, section 5.2.
+// The first half of the key is the MAC key, the second the AES key.
+func cbcHMACEncrypt(cek, iv, plaintext, aad []byte) (ciphertext, tag []byte, err error) {
+	block, err := aes.NewCipher(cek[16:])
+	if err != nil {
+		return nil, nil, err
+	}
+	padding := aes.BlockSize - len(plaintext)%aes.BlockSize
+	ciphertext = make([]byte, len(plaintext)+padding)
+	copy(ciphertext, plaintext)
+	for i := len(plaintext); i < len(ciphertext); i++ {
+		ciphertext[i] = byte(padding)
+	}
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(ciphertext, ciphertext)
+	return ciphertext, cbcHMACTag(cek[:16], iv, ciphertext, aad), nil
+}
+
+func cbcHMACDecrypt(cek, iv, ciphertext, tag, aad []byte) ([]byte, error) {
+	if len(iv) != aes.BlockSize || len(ciphertext) == 0 || len(ciphertext)%aes.BlockSize != 0 {
+		return nil, ErrDecryption
+	}
+	if !hmac.Equal(tag, cbcHMACTag(cek[:16], iv, ciphertext, aad)) {
+		return nil, ErrDecryption
+	}
+	block, err := aes.NewCipher(cek[16:])
+	if err != nil {
+		return nil, err
+	}
+	plaintext := make([]byte, len(ciphertext))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plaintext, ciphertext)
+
+	padding := int(plaintext[len(plaintext)-1])
+	if padding == 0 || padding > aes.BlockSize {
+		return nil, ErrDecryption
+	}
+	expected := make([]byte, padding)
+	for i := range expected {
+		expected[i] = byte(padding)
+	}
+	if subtle.ConstantTimeCompare(plaintext[len(plaintext)-padding:], expected) != 1 {
+		return nil, ErrDecryption
+	}
+	return plaintext[:len(plaintext)-padding], nil
+}
+
+// cbcHMACTag is the first half of HMAC-SHA-256(A || IV || E || AL).
+func cbcHMACTag(macKey, iv, ciphertext, aad []byte) []byte {
+	mac := hmac.New(sha256.New, macKey)
+	mac.Write(aad)
+	mac.Write(iv)
+	mac.Write(ciphertext)
+	mac.Write(binary.BigEndian.AppendUint64(nil, uint64(len(aad))*8))
+	return mac.Sum(nil)[:16]
+}
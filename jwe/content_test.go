@@ -0,0 +1,44 @@
+package jwe
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCBCHMAC(t *testing.T) {
+	// RFC 7518, appendix B.1
+	cek, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f")
+	iv, _ := hex.DecodeString("1af38c2dc2b96ffdd86694092341bc04")
+	plaintext := []byte("A cipher system must not be required to be secret, and it must be able to fall into the hands of the enemy without inconvenience")
+	aad := []byte("The second principle of Auguste Kerckhoffs")
+
+	ciphertext, tag, err := cbcHMACEncrypt(cek, iv, plaintext, aad)
+	assert.NoError(t, err)
+	assert.Equal(t, "c80edfa32ddf39d5ef00c0b468834279a2e46a1b8049f792f76bfe54b903a9c9a94ac9b47ad2655c5f10f9aef71427e2fc6f9b3f399a221489f16362c703233609d45ac69864e3321cf82935ac4096c86e133314c54019e8ca7980dfa4b9cf1b384c486f3a54c51078158ee5d79de59fbd34d848b3d69550a67646344427ade54b8851ffb598f7f80074b9473c82e2db", hex.EncodeToString(ciphertext))
+	assert.Equal(t, "652c3fa36b0a7c5b3219fab3a30bc1c4", hex.EncodeToString(tag))
+
+	got, err := decryptContent(A128CBCHS256, cek, iv, ciphertext, tag, aad)
+	assert.NoError(t, err)
+	assert.Equal(t, plaintext, got)
+
+	_, err = decryptContent(A128CBCHS256, cek, iv, ciphertext, tag, []byte("The first principle"))
+	assert.ErrorIs(t, err, ErrDecryption)
+	_, err = decryptContent(A128CBCHS256, cek, iv, ciphertext[:15], tag, aad)
+	assert.ErrorIs(t, err, ErrDecryption)
+}
+
+func TestGCM(t *testing.T) {
+	cek := make([]byte, 32)
+	iv, ciphertext, tag, err := encryptContent(A256GCM, cek, []byte("trumanwong"), []byte("aad"))
+	assert.NoError(t, err)
+	assert.Len(t, iv, 12)
+	assert.Len(t, tag, 16)
+
+	got, err := decryptContent(A256GCM, cek, iv, ciphertext, tag, []byte("aad"))
+	assert.NoError(t, err)
+	assert.Equal(t, "trumanwong", string(got))
+	_, err = decryptContent(A256GCM, cek, iv, ciphertext, tag[:15], []byte("aad"))
+	assert.ErrorIs(t, err, ErrDecryption)
+}
@@ -0,0 +1,175 @@
+package jwe
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// JSON is the JWE JSON serialization of RFC 7516, section 7.2. Both the
+// general syntax, with Recipients, and the flattened syntax, with Header and
+// EncryptedKey, are decrypted. EncryptJSON writes the general syntax.
+type JSON struct {
+	Protected    string          `json:"protected,omitempty"`
+	Unprotected  *Header         `json:"unprotected,omitempty"`
+	Recipients   []JSONRecipient `json:"recipients,omitempty"`
+	Header       *Header         `json:"header,omitempty"`
+	EncryptedKey string          `json:"encrypted_key,omitempty"`
+	AAD          string          `json:"aad,omitempty"`
+	IV           string          `json:"iv,omitempty"`
+	Ciphertext   string          `json:"ciphertext"`
+	Tag          string          `json:"tag,omitempty"`
+}
+
+// JSONRecipient is the per-recipient part of the general syntax.
+type JSONRecipient struct {
+	Header       *Header `json:"header,omitempty"`
+	EncryptedKey string  `json:"encrypted_key,omitempty"`
+}
+
+// Recipient is a key the content is encrypted to by EncryptJSON.
+type Recipient struct {
+	Alg KeyAlgorithm
+	Kid string
+	// Key is a public or symmetric key, as accepted by Encrypt for Alg.
+	Key interface{}
+}
+
+// EncryptJSON encrypts plaintext to every recipient and return the general
+// JWE JSON serialization. The protected header must set Enc and not Alg,
+// which is per recipient. aad is optional additional authenticated data.
+// dir and ECDH-ES determine the content key, so they can only be used with
+// a single recipient.
+func EncryptJSON(plaintext []byte, protected *Header, aad []byte, recipients ...Recipient) ([]byte, error) {
+	if len(recipients) == 0 {
+		return nil, errors.New("jwe: no recipients")
+	}
+	if protected.Alg != "" || protected.Epk != nil {
+		return nil, errors.New("jwe: alg and epk belong in the recipient headers")
+	}
+	if len(recipients) > 1 {
+		for _, r := range recipients {
+			if r.Alg == Direct || r.Alg == ECDHES {
+				return nil, fmt.Errorf("%w: %s can only be used with a single recipient", ErrInvalidKey, r.Alg)
+			}
+		}
+	}
+	jwe := &JSON{Recipients: make([]JSONRecipient, len(recipients))}
+	var cek []byte
+	for i, r := range recipients {
+		merged := *protected
+		merged.Alg, merged.Kid = r.Alg, r.Kid
+		k, encryptedKey, err := wrapKey(&merged, r.Key, cek)
+		if err != nil {
+			return nil, err
+		}
+		cek = k
+		jwe.Recipients[i] = JSONRecipient{
+			Header:       &Header{Alg: r.Alg, Kid: r.Kid, Epk: merged.Epk},
+			EncryptedKey: encoding.EncodeToString(encryptedKey),
+		}
+	}
+
+	var err error
+	if jwe.Protected, err = encodeHeader(protected); err != nil {
+		return nil, err
+	}
+	if aad != nil {
+		jwe.AAD = encoding.EncodeToString(aad)
+	}
+	iv, ciphertext, tag, err := encryptContent(protected.Enc, cek, plaintext, jwe.additionalData())
+	if err != nil {
+		return nil, err
+	}
+	jwe.IV = encoding.EncodeToString(iv)
+	jwe.Ciphertext = encoding.EncodeToString(ciphertext)
+	jwe.Tag = encoding.EncodeToString(tag)
+	return json.Marshal(jwe)
+}
+
+// DecryptJSON decrypts a general or flattened JWE JSON serialization with
+// key, trying each recipient whose "alg" is allowed. It return the header
+// of the matching recipient merged with the shared headers, the plaintext
+// and the additional authenticated data.
+func DecryptJSON(data []byte, key interface{}, allowed ...KeyAlgorithm) (*Header, []byte, []byte, error) {
+	jwe := new(JSON)
+	if err := json.Unmarshal(data, jwe); err != nil {
+		return nil, nil, nil, fmt.Errorf("%w: malformed json", ErrInvalidToken)
+	}
+	recipients := jwe.Recipients
+	if len(recipients) == 0 {
+		recipients = []JSONRecipient{{Header: jwe.Header, EncryptedKey: jwe.EncryptedKey}}
+	} else if jwe.Header != nil || jwe.EncryptedKey != "" {
+		return nil, nil, nil, fmt.Errorf("%w: mixed general and flattened syntax", ErrInvalidToken)
+	}
+
+	var raw [4][]byte
+	for i, part := range []string{jwe.IV, jwe.Ciphertext, jwe.Tag, jwe.AAD} {
+		b, err := encoding.DecodeString(part)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("%w: malformed json", ErrInvalidToken)
+		}
+		raw[i] = b
+	}
+
+	err := fmt.Errorf("%w: no recipient uses an allowed algorithm", ErrAlgorithmNotAllowed)
+	for _, r := range recipients {
+		header, herr := jwe.header(r.Header)
+		if herr != nil {
+			return nil, nil, nil, herr
+		}
+		if !isAllowed(header.Alg, allowed) {
+			continue
+		}
+		encryptedKey, kerr := encoding.DecodeString(r.EncryptedKey)
+		if kerr != nil {
+			return nil, nil, nil, fmt.Errorf("%w: malformed encrypted_key", ErrInvalidToken)
+		}
+		plaintext, derr := decrypt(header, key, encryptedKey, raw[0], raw[1], raw[2], jwe.additionalData())
+		if derr == nil {
+			return header, plaintext, raw[3], nil
+		}
+		err = derr
+	}
+	return nil, nil, nil, err
+}
+
+// additionalData is the content encryption AAD of RFC 7516, section 5.1.
+func (jwe *JSON) additionalData() []byte {
+	if jwe.AAD == "" {
+		return []byte(jwe.Protected)
+	}
+	return []byte(jwe.Protected + "." + jwe.AAD)
+}
+
+// header merges the protected, shared unprotected and recipient headers,
+// whose member names must be disjoint.
+func (jwe *JSON) header(recipient *Header) (*Header, error) {
+	fields := make(map[string]json.RawMessage)
+	if jwe.Protected != "" {
+		var err error
+		if fields, err = decodeHeader(jwe.Protected); err != nil {
+			return nil, err
+		}
+	}
+	for _, h := range []*Header{jwe.Unprotected, recipient} {
+		if h == nil {
+			continue
+		}
+		b, err := json.Marshal(h)
+		if err != nil {
+			return nil, err
+		}
+		var more map[string]json.RawMessage
+		if err = json.Unmarshal(b, &more); err != nil {
+			return nil, err
+		}
+		for name, value := range more {
+			if _, ok := fields[name]; ok {
+				return nil, fmt.Errorf("%w: duplicate header %q", ErrInvalidToken, name)
+			}
+			fields[name] = value
+		}
+	}
+	return toHeader(fields)
+}
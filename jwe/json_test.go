@@ -0,0 +1,107 @@
+package jwe
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func ExampleEncryptJSON() {
+	alice := []byte("0123456789abcdef0123456789abcdef")
+	bob, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		log.Fatal(err)
+	}
+	data, err := EncryptJSON([]byte("trumanwong"), &Header{Enc: A256GCM}, nil,
+		Recipient{Alg: A256KW, Kid: "alice", Key: alice},
+		Recipient{Alg: ECDHESA256KW, Kid: "bob", Key: &bob.PublicKey},
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	header, plaintext, _, err := DecryptJSON(data, bob, ECDHESA256KW)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(header.Kid, string(plaintext))
+	// Output: bob trumanwong
+}
+
+func TestEncryptJSON(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	secret := []byte("0123456789abcdef0123456789abcdef")
+
+	data, err := EncryptJSON([]byte("trumanwong"), &Header{Enc: A128CBCHS256, Cty: "text/plain"}, []byte("aad"),
+		Recipient{Alg: RSAOAEP256, Key: &rsaKey.PublicKey},
+		Recipient{Alg: A256KW, Key: secret},
+	)
+	assert.NoError(t, err)
+
+	for alg, key := range map[KeyAlgorithm]interface{}{RSAOAEP256: rsaKey, A256KW: secret} {
+		header, plaintext, aad, err := DecryptJSON(data, key, alg)
+		assert.NoError(t, err)
+		assert.Equal(t, &Header{Alg: alg, Enc: A128CBCHS256, Cty: "text/plain"}, header)
+		assert.Equal(t, "trumanwong", string(plaintext))
+		assert.Equal(t, "aad", string(aad))
+	}
+	_, _, _, err = DecryptJSON(data, secret, Direct)
+	assert.ErrorIs(t, err, ErrAlgorithmNotAllowed)
+
+	// the aad is authenticated
+	var jwe JSON
+	assert.NoError(t, json.Unmarshal(data, &jwe))
+	jwe.AAD = encoding.EncodeToString([]byte("other"))
+	tampered, _ := json.Marshal(&jwe)
+	_, _, _, err = DecryptJSON(tampered, secret, A256KW)
+	assert.ErrorIs(t, err, ErrDecryption)
+
+	// dir and ECDH-ES cannot share the content key, wherever they are
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	for _, r := range []Recipient{{Alg: Direct, Key: secret}, {Alg: ECDHES, Key: &ecKey.PublicKey}} {
+		_, err = EncryptJSON(nil, &Header{Enc: A256GCM}, nil, Recipient{Alg: A256KW, Key: secret}, r)
+		assert.ErrorIs(t, err, ErrInvalidKey)
+		_, err = EncryptJSON(nil, &Header{Enc: A256GCM}, nil, r, Recipient{Alg: A256KW, Key: secret})
+		assert.ErrorIs(t, err, ErrInvalidKey)
+	}
+	_, err = EncryptJSON(nil, &Header{Alg: A256KW, Enc: A256GCM}, nil, Recipient{Alg: A256KW, Key: secret})
+	assert.Error(t, err)
+	_, err = EncryptJSON(nil, &Header{Enc: A256GCM}, nil)
+	assert.Error(t, err)
+}
+
+func TestDecryptJSON(t *testing.T) {
+	// a compact token in the flattened syntax
+	secret := []byte("0123456789abcdef0123456789abcdef")
+	data, err := EncryptJSON([]byte("trumanwong"), &Header{Enc: A256GCM}, nil, Recipient{Alg: Direct, Key: secret})
+	assert.NoError(t, err)
+	var general JSON
+	assert.NoError(t, json.Unmarshal(data, &general))
+
+	flattened := general
+	flattened.Header = general.Recipients[0].Header
+	flattened.EncryptedKey = general.Recipients[0].EncryptedKey
+	flattened.Recipients = nil
+	data, _ = json.Marshal(&flattened)
+	_, plaintext, _, err := DecryptJSON(data, secret, Direct)
+	assert.NoError(t, err)
+	assert.Equal(t, "trumanwong", string(plaintext))
+
+	// header names must not repeat across the headers
+	flattened.Unprotected = &Header{Alg: Direct}
+	data, _ = json.Marshal(&flattened)
+	_, _, _, err = DecryptJSON(data, secret, Direct)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	_, _, _, err = DecryptJSON([]byte("{"), secret, Direct)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
@@ -0,0 +1,193 @@
+// Package jwe implements JSON Web Encryption (RFC 7516) in the compact and
+// JSON serializations, with the dir, A256KW, RSA-OAEP-256, ECDH-ES and
+// ECDH-ES+A256KW key management and the A256GCM and A128CBC-HS256 content
+// encryption algorithms of RFC 7518.
+package jwe
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/trumanwong/cryptogo/jwk"
+)
+
+// KeyAlgorithm is a JWE "alg" header value, the key management algorithm.
+type KeyAlgorithm string
+
+const (
+	Direct       KeyAlgorithm = "dir"
+	A256KW       KeyAlgorithm = "A256KW"
+	RSAOAEP256   KeyAlgorithm = "RSA-OAEP-256"
+	ECDHES       KeyAlgorithm = "ECDH-ES"
+	ECDHESA256KW KeyAlgorithm = "ECDH-ES+A256KW"
+)
+
+// ContentEncryption is a JWE "enc" header value.
+type ContentEncryption string
+
+const (
+	A256GCM      ContentEncryption = "A256GCM"
+	A128CBCHS256 ContentEncryption = "A128CBC-HS256"
+)
+
+var (
+	ErrInvalidToken         = errors.New("jwe: invalid token")
+	ErrUnsupportedAlgorithm = errors.New("jwe: unsupported algorithm")
+	ErrAlgorithmNotAllowed  = errors.New("jwe: algorithm not allowed")
+	ErrInvalidKey           = errors.New("jwe: invalid key")
+	ErrDecryption           = errors.New("jwe: decryption failed")
+)
+
+// Header is the JOSE header of a JWE. Apu and Apv are base64url encoded as
+// in the JSON, Epk is set by ECDH-ES.
+type Header struct {
+	Alg KeyAlgorithm      `json:"alg,omitempty"`
+	Enc ContentEncryption `json:"enc,omitempty"`
+	Typ string            `json:"typ,omitempty"`
+	Cty string            `json:"cty,omitempty"`
+	Kid string            `json:"kid,omitempty"`
+	Epk *jwk.Key          `json:"epk,omitempty"`
+	Apu string            `json:"apu,omitempty"`
+	Apv string            `json:"apv,omitempty"`
+	Zip string            `json:"zip,omitempty"`
+}
+
+var encoding = base64.RawURLEncoding
+
+// Encrypt encrypts plaintext to key with alg and enc, return the compact
+// JWE.
+//
+// The key depends on alg:
+//   - dir: the []byte content encryption key, 32 bytes for both enc
+//   - A256KW: a 32 byte []byte key encryption key
+//   - RSA-OAEP-256: *rsa.PublicKey or a public key pem
+//   - ECDH-ES and ECDH-ES+A256KW: *ecc.PublicKey, *ecdsa.PublicKey,
+//     *ecdh.PublicKey or a public key pem on P-256, P-384 or P-521
+func Encrypt(plaintext []byte, alg KeyAlgorithm, enc ContentEncryption, key interface{}) (string, error) {
+	return EncryptWithHeader(plaintext, &Header{Alg: alg, Enc: enc}, key)
+}
+
+// EncryptWithHeader is like Encrypt with a caller supplied header, for
+// example to set kid, cty or the ECDH-ES party info apu and apv.
+func EncryptWithHeader(plaintext []byte, header *Header, key interface{}) (string, error) {
+	h := *header
+	cek, encryptedKey, err := wrapKey(&h, key, nil)
+	if err != nil {
+		return "", err
+	}
+	protected, err := encodeHeader(&h)
+	if err != nil {
+		return "", err
+	}
+	iv, ciphertext, tag, err := encryptContent(h.Enc, cek, plaintext, []byte(protected))
+	if err != nil {
+		return "", err
+	}
+	return strings.Join([]string{
+		protected,
+		encoding.EncodeToString(encryptedKey),
+		encoding.EncodeToString(iv),
+		encoding.EncodeToString(ciphertext),
+		encoding.EncodeToString(tag),
+	}, "."), nil
+}
+
+// Decrypt decrypts a compact JWE with key and return its header and
+// plaintext. The "alg" in the header must be one of allowed, which must not
+// be empty.
+//
+// The key is the private counterpart of the Encrypt key: a []byte key for
+// dir and A256KW, *rsa.PrivateKey, *ecc.PrivateKey, *ecdsa.PrivateKey,
+// *ecdh.PrivateKey or a private key pem.
+func Decrypt(token string, key interface{}, allowed ...KeyAlgorithm) (*Header, []byte, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 5 {
+		return nil, nil, fmt.Errorf("%w: token contains %d parts", ErrInvalidToken, len(parts))
+	}
+	var raw [4][]byte
+	for i, part := range parts[1:] {
+		b, err := encoding.DecodeString(part)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: malformed part %d", ErrInvalidToken, i+2)
+		}
+		raw[i] = b
+	}
+	fields, err := decodeHeader(parts[0])
+	if err != nil {
+		return nil, nil, err
+	}
+	header, err := toHeader(fields)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !isAllowed(header.Alg, allowed) {
+		return nil, nil, fmt.Errorf("%w: %s", ErrAlgorithmNotAllowed, header.Alg)
+	}
+	plaintext, err := decrypt(header, key, raw[0], raw[1], raw[2], raw[3], []byte(parts[0]))
+	if err != nil {
+		return nil, nil, err
+	}
+	return header, plaintext, nil
+}
+
+// decrypt unwraps the content encryption key and decrypts the content.
+func decrypt(header *Header, key interface{}, encryptedKey, iv, ciphertext, tag, aad []byte) ([]byte, error) {
+	if header.Zip != "" {
+		return nil, fmt.Errorf("%w: zip %q", ErrUnsupportedAlgorithm, header.Zip)
+	}
+	cek, err := unwrapKey(header, key, encryptedKey)
+	if err != nil {
+		return nil, err
+	}
+	return decryptContent(header.Enc, cek, iv, ciphertext, tag, aad)
+}
+
+func encodeHeader(header *Header) (string, error) {
+	b, err := json.Marshal(header)
+	if err != nil {
+		return "", err
+	}
+	return encoding.EncodeToString(b), nil
+}
+
+// decodeHeader decodes a base64url protected header into its members.
+func decodeHeader(s string) (map[string]json.RawMessage, error) {
+	b, err := encoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed header", ErrInvalidToken)
+	}
+	var fields map[string]json.RawMessage
+	if err = json.Unmarshal(b, &fields); err != nil {
+		return nil, fmt.Errorf("%w: malformed header", ErrInvalidToken)
+	}
+	return fields, nil
+}
+
+// toHeader decodes the header members, rejecting critical extensions since
+// none are understood.
+func toHeader(fields map[string]json.RawMessage) (*Header, error) {
+	if _, ok := fields["crit"]; ok {
+		return nil, fmt.Errorf("%w: unsupported crit header", ErrInvalidToken)
+	}
+	b, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	header := new(Header)
+	if err = json.Unmarshal(b, header); err != nil {
+		return nil, fmt.Errorf("%w: malformed header", ErrInvalidToken)
+	}
+	return header, nil
+}
+
+func isAllowed(alg KeyAlgorithm, allowed []KeyAlgorithm) bool {
+	for _, a := range allowed {
+		if a == alg {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,398 @@
+package jwe
+
+import (
+	"crypto/aes"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/trumanwong/cryptogo/ecc"
+	"github.com/trumanwong/cryptogo/jwk"
+	cryptorsa "github.com/trumanwong/cryptogo/rsa"
+)
+
+// minRSABits is the minimum modulus size of RFC 7518, section 4.3.
+const minRSABits = 2048
+
+// wrapKey returns the content encryption key and its encrypted form for the
+// recipient key. A cek is generated unless given, which only the key
+// wrapping algorithms accept. ECDH-ES sets header.Epk.
+func wrapKey(header *Header, key interface{}, cek []byte) ([]byte, []byte, error) {
+	size, err := cekSize(header.Enc)
+	if err != nil {
+		return nil, nil, err
+	}
+	switch header.Alg {
+	case Direct, ECDHES:
+		if cek != nil {
+			return nil, nil, fmt.Errorf("%w: %s cannot be used with several recipients", ErrInvalidKey, header.Alg)
+		}
+	default:
+		if cek == nil {
+			cek = make([]byte, size)
+			if _, err = rand.Read(cek); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	switch header.Alg {
+	case Direct:
+		secret, ok := key.([]byte)
+		if !ok || len(secret) != size {
+			return nil, nil, fmt.Errorf("%w: dir requires a %d byte key", ErrInvalidKey, size)
+		}
+		return secret, nil, nil
+	case A256KW:
+		kek, ok := key.([]byte)
+		if !ok || len(kek) != 32 {
+			return nil, nil, fmt.Errorf("%w: A256KW requires a 32 byte key", ErrInvalidKey)
+		}
+		wrapped, err := keyWrap(kek, cek)
+		return cek, wrapped, err
+	case RSAOAEP256:
+		pub, err := rsaPublicKey(key)
+		if err != nil {
+			return nil, nil, err
+		}
+		wrapped, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, pub, cek, nil)
+		return cek, wrapped, err
+	case ECDHES, ECDHESA256KW:
+		pub, err := ecdhPublicKey(key)
+		if err != nil {
+			return nil, nil, err
+		}
+		ephemeral, err := pub.Curve().GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, nil, err
+		}
+		epk, err := ephemeralJWK(ephemeral.PublicKey())
+		if err != nil {
+			return nil, nil, err
+		}
+		header.Epk = epk
+		z, err := ephemeral.ECDH(pub)
+		if err != nil {
+			return nil, nil, err
+		}
+		kek, err := deriveKey(header, z)
+		if err != nil {
+			return nil, nil, err
+		}
+		if header.Alg == ECDHES {
+			return kek, nil, nil
+		}
+		wrapped, err := keyWrap(kek, cek)
+		return cek, wrapped, err
+	}
+	return nil, nil, fmt.Errorf("%w: alg %q", ErrUnsupportedAlgorithm, header.Alg)
+}
+
+// unwrapKey recovers the content encryption key for the recipient key.
+func unwrapKey(header *Header, key interface{}, encryptedKey []byte) ([]byte, error) {
+	size, err := cekSize(header.Enc)
+	if err != nil {
+		return nil, err
+	}
+	var cek []byte
+	switch header.Alg {
+	case Direct, ECDHES:
+		if len(encryptedKey) != 0 {
+			return nil, fmt.Errorf("%w: %s has no encrypted key", ErrInvalidToken, header.Alg)
+		}
+	}
+
+	switch header.Alg {
+	case Direct:
+		secret, ok := key.([]byte)
+		if !ok || len(secret) != size {
+			return nil, fmt.Errorf("%w: dir requires a %d byte key", ErrInvalidKey, size)
+		}
+		cek = secret
+	case A256KW:
+		kek, ok := key.([]byte)
+		if !ok || len(kek) != 32 {
+			return nil, fmt.Errorf("%w: A256KW requires a 32 byte key", ErrInvalidKey)
+		}
+		if cek, err = keyUnwrap(kek, encryptedKey); err != nil {
+			return nil, err
+		}
+	case RSAOAEP256:
+		prv, err := rsaPrivateKey(key)
+		if err != nil {
+			return nil, err
+		}
+		if cek, err = rsa.DecryptOAEP(sha256.New(), nil, prv, encryptedKey, nil); err != nil {
+			return nil, ErrDecryption
+		}
+	case ECDHES, ECDHESA256KW:
+		prv, err := ecdhPrivateKey(key)
+		if err != nil {
+			return nil, err
+		}
+		if header.Epk == nil {
+			return nil, fmt.Errorf("%w: missing epk", ErrInvalidToken)
+		}
+		if header.Epk.Kty != "EC" {
+			return nil, fmt.Errorf("%w: epk is not an EC public key", ErrInvalidKey)
+		}
+		epk, err := header.Epk.ECCPublicKey()
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid epk", ErrInvalidToken)
+		}
+		pub, err := epk.ECDH()
+		if err != nil || pub.Curve() != prv.Curve() {
+			return nil, fmt.Errorf("%w: epk is not on the curve of the key", ErrInvalidToken)
+		}
+		z, err := prv.ECDH(pub)
+		if err != nil {
+			return nil, err
+		}
+		kek, err := deriveKey(header, z)
+		if err != nil {
+			return nil, err
+		}
+		if header.Alg == ECDHES {
+			cek = kek
+		} else if cek, err = keyUnwrap(kek, encryptedKey); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("%w: alg %q", ErrUnsupportedAlgorithm, header.Alg)
+	}
+	if len(cek) != size {
+		return nil, ErrDecryption
+	}
+	return cek, nil
+}
+
+// deriveKey is the Concat KDF of RFC 7518, section 4.6.2. The AlgorithmID
+// is enc for ECDH-ES and alg for ECDH-ES+A256KW.
+func deriveKey(header *Header, z []byte) ([]byte, error) {
+	algID, size := string(header.Enc), 0
+	if header.Alg == ECDHES {
+		var err error
+		if size, err = cekSize(header.Enc); err != nil {
+			return nil, err
+		}
+	} else {
+		algID, size = string(header.Alg), 32
+	}
+	apu, err := encoding.DecodeString(header.Apu)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed apu", ErrInvalidToken)
+	}
+	apv, err := encoding.DecodeString(header.Apv)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed apv", ErrInvalidToken)
+	}
+	return concatKDF(z, algID, apu, apv, size), nil
+}
+
+func concatKDF(z []byte, algID string, apu, apv []byte, size int) []byte {
+	var otherInfo []byte
+	for _, b := range [][]byte{[]byte(algID), apu, apv} {
+		otherInfo = binary.BigEndian.AppendUint32(otherInfo, uint32(len(b)))
+		otherInfo = append(otherInfo, b...)
+	}
+	otherInfo = binary.BigEndian.AppendUint32(otherInfo, uint32(size*8))
+
+	h := sha256.New()
+	var k []byte
+	for counter := uint32(1); len(k) < size; counter++ {
+		h.Reset()
+		h.Write(binary.BigEndian.AppendUint32(nil, counter))
+		h.Write(z)
+		h.Write(otherInfo)
+		k = h.Sum(k)
+	}
+	return k[:size]
+}
+
+// keyWrap is the AES key wrap of RFC 3394.
+func keyWrap(kek, cek []byte) ([]byte, error) {
+	if len(cek)%8 != 0 || len(cek) < 16 {
+		return nil, ErrInvalidKey
+	}
+	block, err := aes.NewCipher(kek)
+	if err != nil {
+		return nil, err
+	}
+	n := len(cek) / 8
+	out := make([]byte, 8+len(cek))
+	copy(out[8:], cek)
+	a := []byte{0xa6, 0xa6, 0xa6, 0xa6, 0xa6, 0xa6, 0xa6, 0xa6}
+	buf := make([]byte, 16)
+	for j := 0; j < 6; j++ {
+		for i := 1; i <= n; i++ {
+			copy(buf, a)
+			copy(buf[8:], out[8*i:8*i+8])
+			block.Encrypt(buf, buf)
+			t := uint64(n*j + i)
+			binary.BigEndian.PutUint64(a, binary.BigEndian.Uint64(buf[:8])^t)
+			copy(out[8*i:], buf[8:])
+		}
+	}
+	copy(out, a)
+	return out, nil
+}
+
+// keyUnwrap reverses keyWrap and checks the integrity value.
+func keyUnwrap(kek, wrapped []byte) ([]byte, error) {
+	if len(wrapped)%8 != 0 || len(wrapped) < 24 {
+		return nil, ErrDecryption
+	}
+	block, err := aes.NewCipher(kek)
+	if err != nil {
+		return nil, err
+	}
+	n := len(wrapped)/8 - 1
+	out := make([]byte, len(wrapped)-8)
+	copy(out, wrapped[8:])
+	a := make([]byte, 8)
+	copy(a, wrapped[:8])
+	buf := make([]byte, 16)
+	for j := 5; j >= 0; j-- {
+		for i := n; i >= 1; i-- {
+			t := uint64(n*j + i)
+			binary.BigEndian.PutUint64(buf, binary.BigEndian.Uint64(a)^t)
+			copy(buf[8:], out[8*(i-1):8*i])
+			block.Decrypt(buf, buf)
+			copy(a, buf[:8])
+			copy(out[8*(i-1):], buf[8:])
+		}
+	}
+	if subtle.ConstantTimeCompare(a, []byte{0xa6, 0xa6, 0xa6, 0xa6, 0xa6, 0xa6, 0xa6, 0xa6}) != 1 {
+		return nil, ErrDecryption
+	}
+	return out, nil
+}
+
+func rsaPublicKey(key interface{}) (*rsa.PublicKey, error) {
+	var pub *rsa.PublicKey
+	switch key := key.(type) {
+	case *rsa.PublicKey:
+		pub = key
+	case *rsa.PrivateKey:
+		pub = &key.PublicKey
+	case []byte:
+		var err error
+		if pub, err = cryptorsa.ParsePublicKey(key); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidKey, err)
+		}
+	default:
+		return nil, fmt.Errorf("%w: %T is not an rsa public key", ErrInvalidKey, key)
+	}
+	if pub.N.BitLen() < minRSABits {
+		return nil, fmt.Errorf("%w: rsa key is smaller than %d bits", ErrInvalidKey, minRSABits)
+	}
+	return pub, nil
+}
+
+func rsaPrivateKey(key interface{}) (*rsa.PrivateKey, error) {
+	var prv *rsa.PrivateKey
+	switch key := key.(type) {
+	case *rsa.PrivateKey:
+		prv = key
+	case []byte:
+		var err error
+		if prv, err = cryptorsa.ParsePrivateKey(key); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidKey, err)
+		}
+	default:
+		return nil, fmt.Errorf("%w: %T is not an rsa private key", ErrInvalidKey, key)
+	}
+	if prv.N.BitLen() < minRSABits {
+		return nil, fmt.Errorf("%w: rsa key is smaller than %d bits", ErrInvalidKey, minRSABits)
+	}
+	return prv, nil
+}
+
+func ecdhPublicKey(key interface{}) (*ecdh.PublicKey, error) {
+	var pub *ecdh.PublicKey
+	var err error
+	switch key := key.(type) {
+	case *ecdh.PublicKey:
+		pub = key
+	case *ecc.PublicKey:
+		pub, err = key.ECDH()
+	case *ecdsa.PublicKey:
+		pub, err = key.ECDH()
+	case []byte:
+		var k *ecc.PublicKey
+		if k, err = ecc.ParsePublicKey(key); err == nil {
+			pub, err = k.ECDH()
+		}
+	default:
+		return nil, fmt.Errorf("%w: %T is not an ec public key", ErrInvalidKey, key)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidKey, err)
+	}
+	if pub.Curve() == ecdh.X25519() {
+		return nil, fmt.Errorf("%w: X25519 is not supported", ErrInvalidKey)
+	}
+	return pub, nil
+}
+
+func ecdhPrivateKey(key interface{}) (*ecdh.PrivateKey, error) {
+	var prv *ecdh.PrivateKey
+	var err error
+	switch key := key.(type) {
+	case *ecdh.PrivateKey:
+		prv = key
+	case *ecc.PrivateKey:
+		prv, err = key.ECDH()
+	case *ecdsa.PrivateKey:
+		prv, err = key.ECDH()
+	case []byte:
+		var k *ecc.PrivateKey
+		if k, err = ecc.ParsePrivateKey(key); err == nil {
+			prv, err = k.ECDH()
+		}
+	default:
+		return nil, fmt.Errorf("%w: %T is not an ec private key", ErrInvalidKey, key)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidKey, err)
+	}
+	if prv.Curve() == ecdh.X25519() {
+		return nil, fmt.Errorf("%w: X25519 is not supported", ErrInvalidKey)
+	}
+	return prv, nil
+}
+
+var ellipticCurves = map[ecdh.Curve]elliptic.Curve{
+	ecdh.P256(): elliptic.P256(),
+	ecdh.P384(): elliptic.P384(),
+	ecdh.P521(): elliptic.P521(),
+}
+
+// ephemeralJWK encodes the ephemeral public key as the epk header.
+func ephemeralJWK(pub *ecdh.PublicKey) (*jwk.Key, error) {
+	k, err := ecc.UnmarshalPublicKey(ellipticCurves[pub.Curve()], pub.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	epk, err := jwk.New(k)
+	if err != nil {
+		return nil, err
+	}
+	epk.Kid = ""
+	return epk, nil
+}
+
+// cekSize returns the content encryption key size of enc.
+func cekSize(enc ContentEncryption) (int, error) {
+	switch enc {
+	case A256GCM, A128CBCHS256:
+		return 32, nil
+	}
+	return 0, fmt.Errorf("%w: enc %q", ErrUnsupportedAlgorithm, enc)
+}
@@ -0,0 +1,45 @@
+package jwe
+
+import (
+	"crypto/ecdh"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyWrap(t *testing.T) {
+	// RFC 3394, section 4.6
+	kek, _ := hex.DecodeString("000102030405060708090A0B0C0D0E0F101112131415161718191A1B1C1D1E1F")
+	key, _ := hex.DecodeString("00112233445566778899AABBCCDDEEFF000102030405060708090A0B0C0D0E0F")
+	wrapped, err := keyWrap(kek, key)
+	assert.NoError(t, err)
+	assert.Equal(t, "28c9f404c4b810f4cbccb35cfb87f8263f5786e2d80ed326cbc7f0e71a99f43bfb988b9b7a02dd21", hex.EncodeToString(wrapped))
+
+	unwrapped, err := keyUnwrap(kek, wrapped)
+	assert.NoError(t, err)
+	assert.Equal(t, key, unwrapped)
+
+	wrapped[0] ^= 1
+	_, err = keyUnwrap(kek, wrapped)
+	assert.ErrorIs(t, err, ErrDecryption)
+	_, err = keyUnwrap(kek, wrapped[:16])
+	assert.ErrorIs(t, err, ErrDecryption)
+}
+
+func TestConcatKDF(t *testing.T) {
+	// RFC 7518, appendix C
+	d := func(s string) []byte {
+		b, _ := encoding.DecodeString(s)
+		return b
+	}
+	alice, err := ecdh.P256().NewPrivateKey(d("0_NxaRPUMQoAJt50Gz8YiTr8gRTwyEaCumd-MToTmIo"))
+	assert.NoError(t, err)
+	bob, err := ecdh.P256().NewPrivateKey(d("VEmDZpDXXK8p8N0Cndsxs924q6nS1RXFASRl6BfUqdw"))
+	assert.NoError(t, err)
+	z, err := alice.ECDH(bob.PublicKey())
+	assert.NoError(t, err)
+
+	key := concatKDF(z, "A128GCM", []byte("Alice"), []byte("Bob"), 16)
+	assert.Equal(t, "VqqN6vgjbSBcIijNcacQGg", encoding.EncodeToString(key))
+}
@@ -0,0 +1,33 @@
+package jwe
+
+import (
+	"fmt"
+
+	"github.com/trumanwong/cryptogo/jwt"
+)
+
+// SignAndEncrypt signs claims as a JWT with sigAlg and signKey, then
+// encrypts the token with alg, enc and encKey into a nested JWT (RFC 7519,
+// section 5.2) with cty "JWT".
+func SignAndEncrypt(claims interface{}, sigAlg jwt.Algorithm, signKey interface{}, alg KeyAlgorithm, enc ContentEncryption, encKey interface{}) (string, error) {
+	token, err := jwt.Sign(claims, sigAlg, signKey)
+	if err != nil {
+		return "", err
+	}
+	return EncryptWithHeader([]byte(token), &Header{Alg: alg, Enc: enc, Cty: "JWT"}, encKey)
+}
+
+// DecryptJWT decrypts a nested JWT and return the signed token inside, to be
+// verified with jwt.Verify, jwt.ParseClaims or a jwt.Verifier. The
+// decryption only proves the sender knew the recipient's public key, so the
+// inner token must always be verified.
+func DecryptJWT(token string, key interface{}, allowed ...KeyAlgorithm) (string, error) {
+	header, plaintext, err := Decrypt(token, key, allowed...)
+	if err != nil {
+		return "", err
+	}
+	if header.Cty != "JWT" && header.Cty != "jwt" {
+		return "", fmt.Errorf("%w: cty %q is not a nested JWT", ErrInvalidToken, header.Cty)
+	}
+	return string(plaintext), nil
+}
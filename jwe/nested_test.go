@@ -0,0 +1,63 @@
+package jwe
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"fmt"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trumanwong/cryptogo/jwt"
+)
+
+func ExampleSignAndEncrypt() {
+	signPub, signKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		log.Fatal(err)
+	}
+	encKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		log.Fatal(err)
+	}
+	token, err := SignAndEncrypt(map[string]string{"sub": "trumanwong"}, jwt.EdDSA, signKey, RSAOAEP256, A256GCM, &encKey.PublicKey)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	inner, err := DecryptJWT(token, encKey, RSAOAEP256)
+	if err != nil {
+		log.Fatal(err)
+	}
+	claims, err := jwt.Parse(inner, signPub, jwt.EdDSA)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(claims["sub"])
+	// Output: trumanwong
+}
+
+func TestDecryptJWT(t *testing.T) {
+	secret := []byte("0123456789abcdef0123456789abcdef")
+	token, err := SignAndEncrypt(jwt.RegisteredClaims{Subject: "trumanwong"}, jwt.HS256, []byte("key"), A256KW, A128CBCHS256, secret)
+	assert.NoError(t, err)
+	header, _, err := Decrypt(token, secret, A256KW)
+	assert.NoError(t, err)
+	assert.Equal(t, "JWT", header.Cty)
+
+	inner, err := DecryptJWT(token, secret, A256KW)
+	assert.NoError(t, err)
+	claims, err := jwt.ParseClaims[jwt.RegisteredClaims](inner, []byte("key"), nil, jwt.HS256)
+	assert.NoError(t, err)
+	assert.Equal(t, "trumanwong", claims.Subject)
+
+	// a plain JWE is not a nested JWT
+	token, err = Encrypt([]byte("trumanwong"), A256KW, A256GCM, secret)
+	assert.NoError(t, err)
+	_, err = DecryptJWT(token, secret, A256KW)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	_, err = SignAndEncrypt(nil, jwt.HS256, []byte("key"), Direct, A256GCM, secret[:16])
+	assert.ErrorIs(t, err, ErrInvalidKey)
+}
@@ -0,0 +1,372 @@
+// Package jwk implements JSON Web Keys (RFC 7517) for RSA, EC, Ed25519 and
+// symmetric keys, with RFC 7638 thumbprints and JWK sets.
+package jwk
+
+import (
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/trumanwong/cryptogo/ecc"
+)
+
+var (
+	ErrUnsupportedKey = errors.New("jwk: unsupported key type")
+	ErrInvalidKey     = errors.New("jwk: invalid key")
+	ErrKeyNotFound    = errors.New("jwk: key not found")
+)
+
+// Key is a JSON Web Key. Members that do not apply to Kty are empty.
+type Key struct {
+	Kty    string   `json:"kty"`
+	Kid    string   `json:"kid,omitempty"`
+	Use    string   `json:"use,omitempty"`
+	Alg    string   `json:"alg,omitempty"`
+	KeyOps []string `json:"key_ops,omitempty"`
+
+	// EC and OKP
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+
+	// RSA
+	N  string `json:"n,omitempty"`
+	E  string `json:"e,omitempty"`
+	P  string `json:"p,omitempty"`
+	Q  string `json:"q,omitempty"`
+	DP string `json:"dp,omitempty"`
+	DQ string `json:"dq,omitempty"`
+	QI string `json:"qi,omitempty"`
+
+	// private exponent of RSA, private scalar of EC and seed of OKP
+	D string `json:"d,omitempty"`
+
+	// oct
+	K string `json:"k,omitempty"`
+}
+
+var encoding = base64.RawURLEncoding
+
+// New creates a JWK from *rsa.PublicKey, *rsa.PrivateKey, *ecdsa.PublicKey,
+// *ecdsa.PrivateKey, *ecc.PublicKey, *ecc.PrivateKey, ed25519.PublicKey,
+// ed25519.PrivateKey or a []byte symmetric key. Kid is set to the RFC 7638
+// thumbprint of the key.
+func New(key interface{}) (*Key, error) {
+	k := new(Key)
+	switch key := key.(type) {
+	case *rsa.PublicKey:
+		k.setRSAPublic(key)
+	case *rsa.PrivateKey:
+		k.setRSAPublic(&key.PublicKey)
+		if len(key.Primes) != 2 {
+			return nil, fmt.Errorf("%w: multi-prime rsa key", ErrUnsupportedKey)
+		}
+		key.Precompute()
+		k.D = encoding.EncodeToString(key.D.Bytes())
+		k.P = encoding.EncodeToString(key.Primes[0].Bytes())
+		k.Q = encoding.EncodeToString(key.Primes[1].Bytes())
+		k.DP = encoding.EncodeToString(key.Precomputed.Dp.Bytes())
+		k.DQ = encoding.EncodeToString(key.Precomputed.Dq.Bytes())
+		k.QI = encoding.EncodeToString(key.Precomputed.Qinv.Bytes())
+	case *ecdsa.PublicKey:
+		if err := k.setECPublic(key.Curve, key.X, key.Y); err != nil {
+			return nil, err
+		}
+	case *ecdsa.PrivateKey:
+		if err := k.setECPublic(key.Curve, key.X, key.Y); err != nil {
+			return nil, err
+		}
+		k.D = encoding.EncodeToString(key.D.FillBytes(make([]byte, curveSize(key.Curve))))
+	case *ecc.PublicKey:
+		return New(key.ExportECDSA())
+	case *ecc.PrivateKey:
+		return New(key.ExportECDSA())
+	case ed25519.PublicKey:
+		if len(key) != ed25519.PublicKeySize {
+			return nil, ErrInvalidKey
+		}
+		k.Kty, k.Crv = "OKP", "Ed25519"
+		k.X = encoding.EncodeToString(key)
+	case ed25519.PrivateKey:
+		if len(key) != ed25519.PrivateKeySize {
+			return nil, ErrInvalidKey
+		}
+		k.Kty, k.Crv = "OKP", "Ed25519"
+		k.X = encoding.EncodeToString(key.Public().(ed25519.PublicKey))
+		k.D = encoding.EncodeToString(key.Seed())
+	case []byte:
+		k.Kty = "oct"
+		k.K = encoding.EncodeToString(key)
+	default:
+		return nil, ErrUnsupportedKey
+	}
+
+	kid, err := k.Thumbprint()
+	if err != nil {
+		return nil, err
+	}
+	k.Kid = kid
+	return k, nil
+}
+
+// Parse parses a single JWK.
+func Parse(data []byte) (*Key, error) {
+	k := new(Key)
+	if err := json.Unmarshal(data, k); err != nil {
+		return nil, err
+	}
+	if _, err := k.Key(); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// IsPrivate reports whether the JWK holds private key material.
+func (k *Key) IsPrivate() bool {
+	return k.D != "" || k.K != ""
+}
+
+// Public returns a copy of the JWK without its private members. A symmetric
+// key has no public part and returns nil.
+func (k *Key) Public() *Key {
+	if k.Kty == "oct" {
+		return nil
+	}
+	pub := *k
+	pub.D, pub.P, pub.Q, pub.DP, pub.DQ, pub.QI = "", "", "", "", "", ""
+	return &pub
+}
+
+// Key returns the Go key held by the JWK: *rsa.PublicKey, *rsa.PrivateKey,
+// *ecdsa.PublicKey, *ecdsa.PrivateKey, ed25519.PublicKey,
+// ed25519.PrivateKey or []byte.
+func (k *Key) Key() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		return k.rsaKey()
+	case "EC":
+		return k.ecKey()
+	case "OKP":
+		return k.okpKey()
+	case "oct":
+		key, err := decode(k.K)
+		if err != nil || len(key) == 0 {
+			return nil, ErrInvalidKey
+		}
+		return key, nil
+	}
+	return nil, ErrUnsupportedKey
+}
+
+// Thumbprint returns the base64url encoded RFC 7638 SHA-256 thumbprint.
+func (k *Key) Thumbprint() (string, error) {
+	// The required members in lexicographic order, as RFC 7638 mandates.
+	var members []string
+	switch k.Kty {
+	case "RSA":
+		members = []string{"e", k.E, "kty", k.Kty, "n", k.N}
+	case "EC":
+		members = []string{"crv", k.Crv, "kty", k.Kty, "x", k.X, "y", k.Y}
+	case "OKP":
+		members = []string{"crv", k.Crv, "kty", k.Kty, "x", k.X}
+	case "oct":
+		members = []string{"k", k.K, "kty", k.Kty}
+	default:
+		return "", ErrUnsupportedKey
+	}
+	buf := []byte{'{'}
+	for i := 0; i < len(members); i += 2 {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		name, _ := json.Marshal(members[i])
+		value, _ := json.Marshal(members[i+1])
+		buf = append(append(append(buf, name...), ':'), value...)
+	}
+	buf = append(buf, '}')
+	sum := sha256.Sum256(buf)
+	return encoding.EncodeToString(sum[:]), nil
+}
+
+// ECCPublicKey returns the EC public key of the JWK as an ecc.PublicKey.
+func (k *Key) ECCPublicKey() (*ecc.PublicKey, error) {
+	if k.Kty != "EC" {
+		return nil, ErrUnsupportedKey
+	}
+	key, err := k.Public().ecKey()
+	if err != nil {
+		return nil, err
+	}
+	return ecc.ImportECDSAPublic(key.(*ecdsa.PublicKey)), nil
+}
+
+// ECCPrivateKey returns the EC private key of the JWK as an ecc.PrivateKey.
+func (k *Key) ECCPrivateKey() (*ecc.PrivateKey, error) {
+	key, err := k.ecKey()
+	if err != nil {
+		return nil, err
+	}
+	prv, ok := key.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, ErrInvalidKey
+	}
+	return ecc.ImportECDSA(prv), nil
+}
+
+func (k *Key) setRSAPublic(key *rsa.PublicKey) {
+	k.Kty = "RSA"
+	k.N = encoding.EncodeToString(key.N.Bytes())
+	k.E = encoding.EncodeToString(big.NewInt(int64(key.E)).Bytes())
+}
+
+func (k *Key) setECPublic(curve elliptic.Curve, x, y *big.Int) error {
+	crv, ok := curveNames[curve]
+	if !ok {
+		return fmt.Errorf("%w: curve %s", ErrUnsupportedKey, curve.Params().Name)
+	}
+	size := curveSize(curve)
+	k.Kty, k.Crv = "EC", crv
+	k.X = encoding.EncodeToString(x.FillBytes(make([]byte, size)))
+	k.Y = encoding.EncodeToString(y.FillBytes(make([]byte, size)))
+	return nil
+}
+
+func (k *Key) rsaKey() (interface{}, error) {
+	n, err := decodeInt(k.N)
+	if err != nil {
+		return nil, err
+	}
+	e, err := decodeInt(k.E)
+	if err != nil {
+		return nil, err
+	}
+	if !e.IsInt64() || e.Int64() > 1<<31-1 {
+		return nil, ErrInvalidKey
+	}
+	pub := &rsa.PublicKey{N: n, E: int(e.Int64())}
+	if k.D == "" {
+		return pub, nil
+	}
+
+	d, err := decodeInt(k.D)
+	if err != nil {
+		return nil, err
+	}
+	p, err := decodeInt(k.P)
+	if err != nil {
+		return nil, err
+	}
+	q, err := decodeInt(k.Q)
+	if err != nil {
+		return nil, err
+	}
+	prv := &rsa.PrivateKey{PublicKey: *pub, D: d, Primes: []*big.Int{p, q}}
+	if err = prv.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidKey, err)
+	}
+	prv.Precompute()
+	return prv, nil
+}
+
+func (k *Key) ecKey() (interface{}, error) {
+	curve, ok := curvesByName[k.Crv]
+	if k.Kty != "EC" || !ok {
+		return nil, ErrUnsupportedKey
+	}
+	size := curveSize(curve)
+	x, err := decode(k.X)
+	if err != nil || len(x) != size {
+		return nil, ErrInvalidKey
+	}
+	y, err := decode(k.Y)
+	if err != nil || len(y) != size {
+		return nil, ErrInvalidKey
+	}
+	// crypto/ecdh rejects points that are not on the curve.
+	point := append(append([]byte{4}, x...), y...)
+	ecdhPub, err := ecdhCurves[k.Crv].NewPublicKey(point)
+	if err != nil {
+		return nil, ErrInvalidKey
+	}
+	pub := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+	if k.D == "" {
+		return pub, nil
+	}
+
+	d, err := decode(k.D)
+	if err != nil || len(d) != size {
+		return nil, ErrInvalidKey
+	}
+	ecdhKey, err := ecdhCurves[k.Crv].NewPrivateKey(d)
+	if err != nil {
+		return nil, ErrInvalidKey
+	}
+	if !ecdhKey.PublicKey().Equal(ecdhPub) {
+		return nil, fmt.Errorf("%w: private key does not match public key", ErrInvalidKey)
+	}
+	return &ecdsa.PrivateKey{PublicKey: *pub, D: new(big.Int).SetBytes(d)}, nil
+}
+
+func (k *Key) okpKey() (interface{}, error) {
+	if k.Crv != "Ed25519" {
+		return nil, ErrUnsupportedKey
+	}
+	x, err := decode(k.X)
+	if err != nil || len(x) != ed25519.PublicKeySize {
+		return nil, ErrInvalidKey
+	}
+	if k.D == "" {
+		return ed25519.PublicKey(x), nil
+	}
+	d, err := decode(k.D)
+	if err != nil || len(d) != ed25519.SeedSize {
+		return nil, ErrInvalidKey
+	}
+	prv := ed25519.NewKeyFromSeed(d)
+	if !prv.Public().(ed25519.PublicKey).Equal(ed25519.PublicKey(x)) {
+		return nil, fmt.Errorf("%w: private key does not match public key", ErrInvalidKey)
+	}
+	return prv, nil
+}
+
+var (
+	curveNames = map[elliptic.Curve]string{
+		elliptic.P256(): "P-256",
+		elliptic.P384(): "P-384",
+		elliptic.P521(): "P-521",
+	}
+	curvesByName = map[string]elliptic.Curve{
+		"P-256": elliptic.P256(),
+		"P-384": elliptic.P384(),
+		"P-521": elliptic.P521(),
+	}
+	ecdhCurves = map[string]ecdh.Curve{
+		"P-256": ecdh.P256(),
+		"P-384": ecdh.P384(),
+		"P-521": ecdh.P521(),
+	}
+)
+
+func curveSize(curve elliptic.Curve) int {
+	return (curve.Params().BitSize + 7) / 8
+}
+
+func decode(s string) ([]byte, error) {
+	return encoding.DecodeString(s)
+}
+
+func decodeInt(s string) (*big.Int, error) {
+	b, err := decode(s)
+	if err != nil || len(b) == 0 {
+		return nil, ErrInvalidKey
+	}
+	return new(big.Int).SetBytes(b), nil
+}
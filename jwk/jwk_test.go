@@ -0,0 +1,160 @@
+package jwk
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/trumanwong/cryptogo/ecc"
+	"log"
+	"testing"
+)
+
+// RFC 7638, section 3.1.
+const rfc7638Key = `{"kty":"RSA","n":"0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw","e":"AQAB","alg":"RS256","kid":"2011-04-29"}`
+
+// RFC 8037, appendix A.1.
+const rfc8037Key = `{"kty":"OKP","crv":"Ed25519","d":"nWGxne_9WmC6hEr0kuwsxERJxWl7MmkZcDusAxyuf2A","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}`
+
+func ExampleKey_Thumbprint() {
+	k, err := Parse([]byte(rfc7638Key))
+	if err != nil {
+		log.Fatal(err)
+	}
+	thumbprint, err := k.Thumbprint()
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(thumbprint)
+	// Output: NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs
+}
+
+func TestParse(t *testing.T) {
+	k, err := Parse([]byte(rfc8037Key))
+	assert.NoError(t, err)
+	thumbprint, err := k.Thumbprint()
+	assert.NoError(t, err)
+	assert.Equal(t, "kPrK_qmxVWaYVA9wwBF6Iuo3vVzz7TxHCTwXBygrS4k", thumbprint)
+
+	key, err := k.Key()
+	assert.NoError(t, err)
+	prv, ok := key.(ed25519.PrivateKey)
+	assert.True(t, ok)
+	sign := ed25519.Sign(prv, []byte("trumanwong"))
+
+	pub, err := k.Public().Key()
+	assert.NoError(t, err)
+	assert.True(t, ed25519.Verify(pub.(ed25519.PublicKey), []byte("trumanwong"), sign))
+
+	tests := []string{
+		`{"kty":"RSA","n":"","e":"AQAB"}`,
+		`{"kty":"EC","crv":"P-256","x":"MKBCTNIcKUSDii11ySs3526iDZ8AiTo7Tu6KPAqv7D4","y":"MKBCTNIcKUSDii11ySs3526iDZ8AiTo7Tu6KPAqv7D4"}`,
+		`{"kty":"OKP","crv":"Ed25519","x":"AQAB"}`,
+		`{"kty":"oct","k":""}`,
+		`{"kty":"XYZ"}`,
+	}
+	for _, v := range tests {
+		_, err = Parse([]byte(v))
+		assert.Error(t, err, v)
+	}
+}
+
+func TestNew(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	assert.NoError(t, err)
+	eccKey, err := ecc.GenerateKey(rand.Reader, elliptic.P384(), nil)
+	assert.NoError(t, err)
+	_, ed25519Key, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		Name string
+		Key  interface{}
+		Kty  string
+	}{
+		{"RSA", rsaKey, "RSA"},
+		{"RSAPublic", &rsaKey.PublicKey, "RSA"},
+		{"ECDSA", ecdsaKey, "EC"},
+		{"ECDSAPublic", &ecdsaKey.PublicKey, "EC"},
+		{"ECC", eccKey, "EC"},
+		{"ECCPublic", &eccKey.PublicKey, "EC"},
+		{"Ed25519", ed25519Key, "OKP"},
+		{"Ed25519Public", ed25519Key.Public(), "OKP"},
+		{"Oct", []byte("0123456789abcdef"), "oct"},
+	}
+	for _, v := range tests {
+		t.Run(v.Name, func(t *testing.T) {
+			k, err := New(v.Key)
+			assert.NoError(t, err)
+			assert.Equal(t, v.Kty, k.Kty)
+			assert.NotEmpty(t, k.Kid)
+
+			data, err := json.Marshal(k)
+			assert.NoError(t, err)
+			parsed, err := Parse(data)
+			assert.NoError(t, err)
+			assert.Equal(t, k, parsed)
+
+			if pub := k.Public(); pub != nil {
+				assert.False(t, pub.IsPrivate())
+				assert.Equal(t, k.Kid, pub.Kid)
+			}
+		})
+	}
+
+	t.Run("ECCKey", func(t *testing.T) {
+		k, err := New(eccKey)
+		assert.NoError(t, err)
+		prv, err := k.ECCPrivateKey()
+		assert.NoError(t, err)
+		assert.Equal(t, eccKey.D, prv.D)
+		assert.Equal(t, eccKey.Params, prv.Params)
+		pub, err := k.ECCPublicKey()
+		assert.NoError(t, err)
+		assert.Equal(t, eccKey.PublicKey.X, pub.X)
+
+		for _, k := range []*Key{{Kty: "oct", K: "AAAA"}, {Kty: "OKP", Crv: "Ed25519"}, {}} {
+			_, err = k.ECCPublicKey()
+			assert.ErrorIs(t, err, ErrUnsupportedKey)
+			_, err = k.ECCPrivateKey()
+			assert.ErrorIs(t, err, ErrUnsupportedKey)
+		}
+	})
+
+	t.Run("RSAKey", func(t *testing.T) {
+		k, err := New(rsaKey)
+		assert.NoError(t, err)
+		key, err := k.Key()
+		assert.NoError(t, err)
+		assert.True(t, rsaKey.Equal(key))
+	})
+
+	_, err = New("trumanwong")
+	assert.ErrorIs(t, err, ErrUnsupportedKey)
+}
+
+func TestPEM(t *testing.T) {
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	k, err := New(ecdsaKey)
+	assert.NoError(t, err)
+
+	for _, v := range []*Key{k, k.Public()} {
+		data, err := v.PEM()
+		assert.NoError(t, err)
+		ret, err := FromPEM(data)
+		assert.NoError(t, err)
+		assert.Equal(t, v, ret)
+	}
+
+	oct, err := New([]byte("key"))
+	assert.NoError(t, err)
+	_, err = oct.PEM()
+	assert.Error(t, err)
+}
@@ -0,0 +1,66 @@
+package jwk
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+)
+
+// FromPEM creates a JWK from a pem encoded key. PKCS1 (RSA PUBLIC KEY, RSA
+// PRIVATE KEY), PKIX (PUBLIC KEY), PKCS8 (PRIVATE KEY) and SEC1 (EC PRIVATE
+// KEY) are supported.
+func FromPEM(data []byte) (*Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("jwk: key is invalid format")
+	}
+
+	var key interface{}
+	var err error
+	switch block.Type {
+	case "RSA PUBLIC KEY":
+		key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	case "PUBLIC KEY":
+		key, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		return nil, errors.New("jwk: key is invalid format")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return New(key)
+}
+
+// PEM encodes the key held by the JWK, a public key as PKIX (PUBLIC KEY)
+// and a private key as PKCS8 (PRIVATE KEY). Symmetric keys have no pem form.
+func (k *Key) PEM() ([]byte, error) {
+	key, err := k.Key()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := key.([]byte); ok {
+		return nil, ErrUnsupportedKey
+	}
+
+	var block *pem.Block
+	if k.IsPrivate() {
+		der, err := x509.MarshalPKCS8PrivateKey(key)
+		if err != nil {
+			return nil, err
+		}
+		block = &pem.Block{Type: "PRIVATE KEY", Bytes: der}
+	} else {
+		der, err := x509.MarshalPKIXPublicKey(key)
+		if err != nil {
+			return nil, err
+		}
+		block = &pem.Block{Type: "PUBLIC KEY", Bytes: der}
+	}
+	return pem.EncodeToMemory(block), nil
+}
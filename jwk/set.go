@@ -0,0 +1,66 @@
+package jwk
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// Set is a JWK Set, as published at /.well-known/jwks.json.
+type Set struct {
+	Keys []*Key `json:"keys"`
+}
+
+// NewSet creates a JWK Set holding keys.
+func NewSet(keys ...*Key) *Set {
+	return &Set{Keys: keys}
+}
+
+// ParseSet parses a JWK Set. Keys with an unsupported kty are skipped as
+// RFC 7517 section 5 requires, invalid keys are an error.
+func ParseSet(data []byte) (*Set, error) {
+	var raw struct {
+		Keys []json.RawMessage `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	set := new(Set)
+	for _, data := range raw.Keys {
+		k, err := Parse(data)
+		if errors.Is(err, ErrUnsupportedKey) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		set.Keys = append(set.Keys, k)
+	}
+	return set, nil
+}
+
+// Add appends keys to the set.
+func (s *Set) Add(keys ...*Key) {
+	s.Keys = append(s.Keys, keys...)
+}
+
+// Lookup returns the first key whose kid matches.
+func (s *Set) Lookup(kid string) (*Key, error) {
+	for _, k := range s.Keys {
+		if k.Kid == kid {
+			return k, nil
+		}
+	}
+	return nil, ErrKeyNotFound
+}
+
+// Public returns a set with the public part of every asymmetric key, ready
+// to be published. Symmetric keys are left out.
+func (s *Set) Public() *Set {
+	pub := new(Set)
+	for _, k := range s.Keys {
+		if p := k.Public(); p != nil {
+			pub.Keys = append(pub.Keys, p)
+		}
+	}
+	return pub
+}
@@ -0,0 +1,53 @@
+package jwk
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"log"
+	"testing"
+)
+
+func ExampleSet_Lookup() {
+	set, err := ParseSet([]byte(`{"keys":[` + rfc7638Key + `,` + rfc8037Key + `]}`))
+	if err != nil {
+		log.Fatal(err)
+	}
+	k, err := set.Lookup("2011-04-29")
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(k.Kty, k.Alg)
+	// Output: RSA RS256
+}
+
+func TestSet(t *testing.T) {
+	oct, err := New([]byte("0123456789abcdef"))
+	assert.NoError(t, err)
+	okp, err := Parse([]byte(rfc8037Key))
+	assert.NoError(t, err)
+	okp.Kid = "okp"
+
+	set := NewSet(oct)
+	set.Add(okp)
+	_, err = set.Lookup("missing")
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+	k, err := set.Lookup("okp")
+	assert.NoError(t, err)
+	assert.Equal(t, okp, k)
+
+	pub := set.Public()
+	assert.Len(t, pub.Keys, 1)
+	assert.False(t, pub.Keys[0].IsPrivate())
+
+	data, err := json.Marshal(pub)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"keys":[{"kty":"OKP","kid":"okp","crv":"Ed25519","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}]}`, string(data))
+
+	parsed, err := ParseSet([]byte(`{"keys":[{"kty":"unknown"},` + rfc8037Key + `]}`))
+	assert.NoError(t, err)
+	assert.Len(t, parsed.Keys, 1)
+
+	_, err = ParseSet([]byte(`{"keys":[{"kty":"oct","k":"!"}]}`))
+	assert.Error(t, err)
+}
@@ -1,27 +1,28 @@
 package cryptogo
 
 import (
-	"fmt"
-	"github.com/golang-jwt/jwt"
+	"github.com/trumanwong/cryptogo/jwt"
 )
 
-func JwtEncrypt(key []byte, claims jwt.MapClaims) (string, error) {
-	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(key))
+// JwtEncrypt sign claims with key using HS256, return the token.
+func JwtEncrypt(key []byte, claims map[string]interface{}) (string, error) {
+	return jwt.Sign(claims, jwt.HS256, key)
 }
 
-func JwtDecrypt(tokenString string, key []byte) (jwt.MapClaims, error) {
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return key, nil
-	})
-	if err != nil {
-		return nil, err
-	}
-	claims, ok := token.Claims.(jwt.MapClaims)
-	if !ok {
-		return nil, fmt.Errorf("token claims is invalid")
-	}
-	return claims, nil
+// JwtDecrypt verify the HS256, HS384 or HS512 token with key and return its
+// claims. exp, nbf and iat are checked if present.
+func JwtDecrypt(tokenString string, key []byte) (map[string]interface{}, error) {
+	return jwt.Parse(tokenString, key, jwt.HS256, jwt.HS384, jwt.HS512)
+}
+
+// JwtEncryptClaims sign a custom claims struct, typically embedding
+// jwt.RegisteredClaims, with key using HS256, return the token.
+func JwtEncryptClaims(key []byte, claims interface{}) (string, error) {
+	return jwt.Sign(claims, jwt.HS256, key)
+}
+
+// JwtDecryptClaims verify the token like JwtDecrypt, validate its claims
+// with v (nil only checks exp, nbf and iat) and decode them into T.
+func JwtDecryptClaims[T any](tokenString string, key []byte, v *jwt.Validator) (*T, error) {
+	return jwt.ParseClaims[T](tokenString, key, v, jwt.HS256, jwt.HS384, jwt.HS512)
 }
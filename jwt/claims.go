@@ -0,0 +1,191 @@
+package jwt
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+var (
+	ErrTokenExpired          = errors.New("jwt: token is expired")
+	ErrTokenNotValidYet      = errors.New("jwt: token is not valid yet")
+	ErrTokenUsedBeforeIssued = errors.New("jwt: token used before issued")
+	ErrInvalidIssuer         = errors.New("jwt: invalid issuer")
+	ErrInvalidAudience       = errors.New("jwt: invalid audience")
+	ErrMissingClaim          = errors.New("jwt: missing required claim")
+)
+
+// NumericDate is a JSON number of seconds since the epoch, RFC 7519 section 2.
+type NumericDate struct {
+	time.Time
+}
+
+// NewNumericDate truncates t to seconds, the precision of the JSON number.
+func NewNumericDate(t time.Time) *NumericDate {
+	return &NumericDate{t.Truncate(time.Second)}
+}
+
+// MarshalJSON encodes the date as an integer number of seconds.
+func (d NumericDate) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.FormatInt(d.Unix(), 10)), nil
+}
+
+// UnmarshalJSON decodes an integer or fractional number of seconds.
+func (d *NumericDate) UnmarshalJSON(b []byte) error {
+	var n json.Number
+	if err := json.Unmarshal(b, &n); err != nil {
+		return err
+	}
+	f, err := n.Float64()
+	if err != nil {
+		return err
+	}
+	sec, frac := math.Modf(f)
+	d.Time = time.Unix(int64(sec), int64(frac*1e9))
+	return nil
+}
+
+// Audience is the "aud" claim, a single string or an array of strings.
+type Audience []string
+
+// MarshalJSON encodes a single audience as a string, like most issuers do.
+func (a Audience) MarshalJSON() ([]byte, error) {
+	if len(a) == 1 {
+		return json.Marshal(a[0])
+	}
+	return json.Marshal([]string(a))
+}
+
+// UnmarshalJSON decodes a string or an array of strings.
+func (a *Audience) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		*a = Audience{s}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(b, &list); err != nil {
+		return err
+	}
+	*a = list
+	return nil
+}
+
+// Contains reports whether aud is one of the audiences.
+func (a Audience) Contains(aud string) bool {
+	for _, v := range a {
+		if v == aud {
+			return true
+		}
+	}
+	return false
+}
+
+// RegisteredClaims are the registered claims of RFC 7519, section 4.1.
+// Embed it in a struct to add private claims:
+//
+//	type Claims struct {
+//		jwt.RegisteredClaims
+//		Role string `json:"role"`
+//	}
+type RegisteredClaims struct {
+	Issuer    string       `json:"iss,omitempty"`
+	Subject   string       `json:"sub,omitempty"`
+	Audience  Audience     `json:"aud,omitempty"`
+	ExpiresAt *NumericDate `json:"exp,omitempty"`
+	NotBefore *NumericDate `json:"nbf,omitempty"`
+	IssuedAt  *NumericDate `json:"iat,omitempty"`
+	ID        string       `json:"jti,omitempty"`
+}
+
+// Validator is the claim validation policy applied after the signature is
+// verified. The zero value only checks exp, nbf and iat when present.
+type Validator struct {
+	// Issuer is the expected "iss", empty means any.
+	Issuer string
+	// Audience must be one of "aud", empty means any.
+	Audience string
+	// Required claims must be present, for example "exp" or "sub".
+	Required []string
+	// Leeway is the allowed clock skew for exp, nbf, iat and MaxAge.
+	Leeway time.Duration
+	// MaxAge rejects tokens issued longer ago, it requires "iat".
+	MaxAge time.Duration
+	// Now returns the current time, time.Now if nil.
+	Now func() time.Time
+}
+
+// Validate checks the raw JSON claims of a verified token against the
+// policy. A nil Validator is the zero value.
+func (v *Validator) Validate(payload []byte) error {
+	if v == nil {
+		v = &Validator{}
+	}
+	var present map[string]json.RawMessage
+	if err := unmarshal(payload, &present); err != nil {
+		return err
+	}
+	for _, name := range v.Required {
+		if _, ok := present[name]; !ok {
+			return fmt.Errorf("%w: %s", ErrMissingClaim, name)
+		}
+	}
+	if v.MaxAge > 0 {
+		if _, ok := present["iat"]; !ok {
+			return fmt.Errorf("%w: iat", ErrMissingClaim)
+		}
+	}
+	var claims RegisteredClaims
+	if err := unmarshal(payload, &claims); err != nil {
+		return err
+	}
+	return v.validate(&claims)
+}
+
+func (v *Validator) validate(claims *RegisteredClaims) error {
+	now := time.Now()
+	if v.Now != nil {
+		now = v.Now()
+	}
+	if claims.ExpiresAt != nil && !now.Before(claims.ExpiresAt.Add(v.Leeway)) {
+		return fmt.Errorf("%w: expired at %s", ErrTokenExpired, claims.ExpiresAt.UTC())
+	}
+	if claims.NotBefore != nil && now.Add(v.Leeway).Before(claims.NotBefore.Time) {
+		return fmt.Errorf("%w: valid from %s", ErrTokenNotValidYet, claims.NotBefore.UTC())
+	}
+	if claims.IssuedAt != nil {
+		if now.Add(v.Leeway).Before(claims.IssuedAt.Time) {
+			return fmt.Errorf("%w: issued at %s", ErrTokenUsedBeforeIssued, claims.IssuedAt.UTC())
+		}
+		if v.MaxAge > 0 && now.Sub(claims.IssuedAt.Time) > v.MaxAge+v.Leeway {
+			return fmt.Errorf("%w: issued more than %s ago", ErrTokenExpired, v.MaxAge)
+		}
+	}
+	if v.Issuer != "" && claims.Issuer != v.Issuer {
+		return fmt.Errorf("%w: %q", ErrInvalidIssuer, claims.Issuer)
+	}
+	if v.Audience != "" && !claims.Audience.Contains(v.Audience) {
+		return fmt.Errorf("%w: %q", ErrInvalidAudience, []string(claims.Audience))
+	}
+	return nil
+}
+
+// ParseClaims verifies token like Verify, validates its claims with v and
+// decodes them into T, typically a struct embedding RegisteredClaims.
+func ParseClaims[T any](token string, key interface{}, v *Validator, allowed ...Algorithm) (*T, error) {
+	_, payload, err := Verify(token, key, allowed...)
+	if err != nil {
+		return nil, err
+	}
+	if err = v.Validate(payload); err != nil {
+		return nil, err
+	}
+	claims := new(T)
+	if err = unmarshal(payload, claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
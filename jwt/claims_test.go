@@ -0,0 +1,125 @@
+package jwt
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type userClaims struct {
+	RegisteredClaims
+	Role string `json:"role"`
+}
+
+func ExampleParseClaims() {
+	key := []byte("trumanwong")
+	now := time.Unix(1700000000, 0)
+	token, err := Sign(userClaims{
+		RegisteredClaims: RegisteredClaims{
+			Issuer:    "https://auth.example.com",
+			Subject:   "trumanwong",
+			Audience:  Audience{"api"},
+			ExpiresAt: NewNumericDate(now.Add(time.Hour)),
+			IssuedAt:  NewNumericDate(now),
+		},
+		Role: "admin",
+	}, HS256, key)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	v := &Validator{
+		Issuer:   "https://auth.example.com",
+		Audience: "api",
+		Required: []string{"exp", "sub"},
+		Leeway:   time.Minute,
+		Now:      func() time.Time { return now.Add(30 * time.Minute) },
+	}
+	claims, err := ParseClaims[userClaims](token, key, v, HS256)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(claims.Subject, claims.Role)
+	// Output: trumanwong admin
+}
+
+func TestValidator(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	clock := func() time.Time { return now }
+	claims := RegisteredClaims{
+		Issuer:    "iss",
+		Audience:  Audience{"a", "b"},
+		ExpiresAt: NewNumericDate(now.Add(time.Minute)),
+		NotBefore: NewNumericDate(now.Add(-time.Minute)),
+		IssuedAt:  NewNumericDate(now.Add(-time.Minute)),
+	}
+	payload, err := json.Marshal(claims)
+	assert.NoError(t, err)
+	assert.NoError(t, (&Validator{Issuer: "iss", Audience: "b", Now: clock}).Validate(payload))
+
+	tests := []struct {
+		v   *Validator
+		err error
+	}{
+		{&Validator{Issuer: "other", Now: clock}, ErrInvalidIssuer},
+		{&Validator{Audience: "c", Now: clock}, ErrInvalidAudience},
+		{&Validator{Required: []string{"sub"}, Now: clock}, ErrMissingClaim},
+		{&Validator{Now: func() time.Time { return now.Add(time.Minute) }}, ErrTokenExpired},
+		{&Validator{Now: func() time.Time { return now.Add(-2 * time.Minute) }}, ErrTokenNotValidYet},
+		{&Validator{MaxAge: 30 * time.Second, Now: clock}, ErrTokenExpired},
+	}
+	for _, test := range tests {
+		assert.ErrorIs(t, test.v.Validate(payload), test.err)
+	}
+
+	// the leeway absorbs clock skew
+	assert.NoError(t, (&Validator{Leeway: time.Minute, Now: func() time.Time { return now.Add(time.Minute) }}).Validate(payload))
+	assert.NoError(t, (&Validator{Leeway: time.Minute, Now: func() time.Time { return now.Add(-2 * time.Minute) }}).Validate(payload))
+	assert.NoError(t, (&Validator{MaxAge: 30 * time.Second, Leeway: time.Minute, Now: clock}).Validate(payload))
+
+	// iat in the future, and MaxAge without iat
+	payload, _ = json.Marshal(RegisteredClaims{IssuedAt: NewNumericDate(now.Add(time.Minute))})
+	assert.ErrorIs(t, (&Validator{Now: clock}).Validate(payload), ErrTokenUsedBeforeIssued)
+	assert.ErrorIs(t, (&Validator{MaxAge: time.Hour, Now: clock}).Validate([]byte(`{}`)), ErrMissingClaim)
+
+	// a nil Validator only checks the time claims
+	assert.NoError(t, (*Validator)(nil).Validate([]byte(`{"aud":"x"}`)))
+	assert.ErrorIs(t, (*Validator)(nil).Validate([]byte(`{"exp":1}`)), ErrTokenExpired)
+	assert.ErrorIs(t, (*Validator)(nil).Validate([]byte(`{"exp":"tomorrow"}`)), ErrInvalidToken)
+}
+
+func TestParseClaims(t *testing.T) {
+	key := []byte("trumanwong")
+	token, err := Sign(map[string]interface{}{"sub": "trumanwong", "exp": 1}, HS256, key)
+	assert.NoError(t, err)
+	_, err = Parse(token, key, HS256)
+	assert.ErrorIs(t, err, ErrTokenExpired)
+	_, err = ParseClaims[userClaims](token, key, &Validator{Now: func() time.Time { return time.Unix(0, 0) }}, HS256)
+	assert.NoError(t, err)
+
+	// the signature is checked before the claims
+	_, err = ParseClaims[userClaims](token, []byte("other"), nil, HS256)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+}
+
+func TestClaimsJSON(t *testing.T) {
+	var claims RegisteredClaims
+	assert.NoError(t, json.Unmarshal([]byte(`{"aud":"a","exp":1700000000.5}`), &claims))
+	assert.Equal(t, Audience{"a"}, claims.Audience)
+	assert.Equal(t, time.Unix(1700000000, 5e8), claims.ExpiresAt.Time)
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"aud":["a","b"]}`), &claims))
+	assert.Equal(t, Audience{"a", "b"}, claims.Audience)
+	assert.Error(t, json.Unmarshal([]byte(`{"aud":1}`), &claims))
+
+	b, err := json.Marshal(RegisteredClaims{Audience: Audience{"a"}, IssuedAt: NewNumericDate(time.Unix(1700000000, 5e8))})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"aud":"a","iat":1700000000}`, string(b))
+	b, err = json.Marshal(Audience{"a", "b"})
+	assert.NoError(t, err)
+	assert.Equal(t, `["a","b"]`, string(b))
+}
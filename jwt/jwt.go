@@ -0,0 +1,180 @@
+// Package jwt implements signed JSON Web Tokens (RFC 7519) in the JWS compact
+// serialization (RFC 7515) with the HMAC, RSA, RSA-PSS, ECDSA and EdDSA
+// algorithms of RFC 7518 and RFC 8037.
+package jwt
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Algorithm is a JWS "alg" header value.
+type Algorithm string
+
+const (
+	HS256 Algorithm = "HS256"
+	HS384 Algorithm = "HS384"
+	HS512 Algorithm = "HS512"
+	RS256 Algorithm = "RS256"
+	RS384 Algorithm = "RS384"
+	RS512 Algorithm = "RS512"
+	PS256 Algorithm = "PS256"
+	PS384 Algorithm = "PS384"
+	PS512 Algorithm = "PS512"
+	ES256 Algorithm = "ES256"
+	ES384 Algorithm = "ES384"
+	ES512 Algorithm = "ES512"
+	EdDSA Algorithm = "EdDSA"
+)
+
+var (
+	ErrInvalidToken         = errors.New("jwt: invalid token")
+	ErrUnsupportedAlgorithm = errors.New("jwt: unsupported algorithm")
+	ErrAlgorithmNotAllowed  = errors.New("jwt: algorithm not allowed")
+	ErrInvalidKey           = errors.New("jwt: invalid key")
+	ErrInvalidSignature     = errors.New("jwt: invalid signature")
+)
+
+// Header is the JOSE header of a token.
+type Header struct {
+	Alg Algorithm `json:"alg"`
+	Typ string    `json:"typ,omitempty"`
+	Cty string    `json:"cty,omitempty"`
+	Kid string    `json:"kid,omitempty"`
+}
+
+var encoding = base64.RawURLEncoding
+
+// Sign encodes claims as JSON and signs them with alg and key, return the
+// compact token. The header is {"alg":alg,"typ":"JWT"}.
+//
+// The key depends on the algorithm family:
+//   - HS*: a []byte secret, PEM keys are rejected
+//   - RS* and PS*: *rsa.PrivateKey or a PKCS1/PKCS8 pem
+//   - ES*: *ecc.PrivateKey, *ecdsa.PrivateKey or a SEC1/PKCS8 pem on the
+//     curve of the algorithm (P-256, P-384 or P-521)
+//   - EdDSA: ed25519.PrivateKey or a PKCS8 pem
+func Sign(claims interface{}, alg Algorithm, key interface{}) (string, error) {
+	return SignWithHeader(&Header{Alg: alg, Typ: "JWT"}, claims, key)
+}
+
+// SignWithHeader is like Sign with a caller supplied header, for example to
+// set kid.
+func SignWithHeader(header *Header, claims interface{}, key interface{}) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	return signPayload(header, payload, key)
+}
+
+// Verify checks the signature of token with key and return its header and
+// raw JSON payload. The algorithm in the header must be one of allowed,
+// which must not be empty, so a token cannot choose how it is verified.
+//
+// The key is the public counterpart of the Sign key: a []byte secret for
+// HS*, *rsa.PublicKey, *ecc.PublicKey, *ecdsa.PublicKey, ed25519.PublicKey
+// or a public key pem (X.509 certificates for RSA and EdDSA). Private keys
+// are accepted too.
+func Verify(token string, key interface{}, allowed ...Algorithm) (*Header, []byte, error) {
+	header, signingInput, payload, sig, err := split(token)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !isAllowed(header.Alg, allowed) {
+		return nil, nil, fmt.Errorf("%w: %s", ErrAlgorithmNotAllowed, header.Alg)
+	}
+	m, err := method(header.Alg)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = m.verify(signingInput, sig, key); err != nil {
+		return nil, nil, err
+	}
+	return header, payload, nil
+}
+
+// Parse verifies token like Verify, checks exp, nbf and iat if present and
+// decodes its claims into a map. Use ParseClaims for a stricter policy.
+func Parse(token string, key interface{}, allowed ...Algorithm) (map[string]interface{}, error) {
+	claims, err := ParseClaims[map[string]interface{}](token, key, nil, allowed...)
+	if err != nil {
+		return nil, err
+	}
+	return *claims, nil
+}
+
+// ParseHeader decodes the header of token without verifying it, for example
+// to read kid. Nothing in it can be trusted before Verify succeeds.
+func ParseHeader(token string) (*Header, error) {
+	header, _, _, _, err := split(token)
+	return header, err
+}
+
+func signPayload(header *Header, payload []byte, key interface{}) (string, error) {
+	m, err := method(header.Alg)
+	if err != nil {
+		return "", err
+	}
+	h, err := json.Marshal(header)
+	if err != nil {
+		return "", err
+	}
+	signingInput := encoding.EncodeToString(h) + "." + encoding.EncodeToString(payload)
+	sig, err := m.sign(signingInput, key)
+	if err != nil {
+		return "", err
+	}
+	return signingInput + "." + encoding.EncodeToString(sig), nil
+}
+
+// split decodes the three parts of a compact token.
+func split(token string) (header *Header, signingInput string, payload, sig []byte, err error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, "", nil, nil, fmt.Errorf("%w: token contains %d parts", ErrInvalidToken, len(parts))
+	}
+	h, err := encoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, "", nil, nil, fmt.Errorf("%w: malformed header", ErrInvalidToken)
+	}
+	var fields map[string]json.RawMessage
+	if err = json.Unmarshal(h, &fields); err != nil {
+		return nil, "", nil, nil, fmt.Errorf("%w: malformed header", ErrInvalidToken)
+	}
+	// no critical extensions are understood (RFC 7515, section 4.1.11)
+	if _, ok := fields["crit"]; ok {
+		return nil, "", nil, nil, fmt.Errorf("%w: unsupported crit header", ErrInvalidToken)
+	}
+	header = new(Header)
+	if err = json.Unmarshal(h, header); err != nil {
+		return nil, "", nil, nil, fmt.Errorf("%w: malformed header", ErrInvalidToken)
+	}
+	if payload, err = encoding.DecodeString(parts[1]); err != nil {
+		return nil, "", nil, nil, fmt.Errorf("%w: malformed payload", ErrInvalidToken)
+	}
+	if sig, err = encoding.DecodeString(parts[2]); err != nil {
+		return nil, "", nil, nil, fmt.Errorf("%w: malformed signature", ErrInvalidToken)
+	}
+	return header, parts[0] + "." + parts[1], payload, sig, nil
+}
+
+// unmarshal decodes a JSON payload, malformed claims are ErrInvalidToken.
+func unmarshal(payload []byte, v interface{}) error {
+	if err := json.Unmarshal(payload, v); err != nil {
+		return fmt.Errorf("%w: malformed claims", ErrInvalidToken)
+	}
+	return nil
+}
+
+func isAllowed(alg Algorithm, allowed []Algorithm) bool {
+	for _, a := range allowed {
+		if a == alg {
+			return true
+		}
+	}
+	return false
+}
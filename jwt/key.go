@@ -0,0 +1,247 @@
+package jwt
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"fmt"
+
+	"github.com/trumanwong/cryptogo/ecc"
+	cryptoed25519 "github.com/trumanwong/cryptogo/ed25519"
+	cryptorsa "github.com/trumanwong/cryptogo/rsa"
+)
+
+// minRSABits is the minimum modulus size of RFC 7518, section 3.3.
+const minRSABits = 2048
+
+// signingMethod signs and verifies the JWS signing input of one algorithm.
+type signingMethod interface {
+	sign(signingInput string, key interface{}) ([]byte, error)
+	verify(signingInput string, sig []byte, key interface{}) error
+}
+
+type hmacMethod struct{ hash crypto.Hash }
+
+type rsaMethod struct {
+	hash crypto.Hash
+	pss  bool
+}
+
+type ecdsaMethod struct {
+	hash  crypto.Hash
+	curve elliptic.Curve
+}
+
+type eddsaMethod struct{}
+
+var methods = map[Algorithm]signingMethod{
+	HS256: hmacMethod{crypto.SHA256},
+	HS384: hmacMethod{crypto.SHA384},
+	HS512: hmacMethod{crypto.SHA512},
+	RS256: rsaMethod{hash: crypto.SHA256},
+	RS384: rsaMethod{hash: crypto.SHA384},
+	RS512: rsaMethod{hash: crypto.SHA512},
+	PS256: rsaMethod{hash: crypto.SHA256, pss: true},
+	PS384: rsaMethod{hash: crypto.SHA384, pss: true},
+	PS512: rsaMethod{hash: crypto.SHA512, pss: true},
+	ES256: ecdsaMethod{crypto.SHA256, elliptic.P256()},
+	ES384: ecdsaMethod{crypto.SHA384, elliptic.P384()},
+	ES512: ecdsaMethod{crypto.SHA512, elliptic.P521()},
+	EdDSA: eddsaMethod{},
+}
+
+func method(alg Algorithm) (signingMethod, error) {
+	m, ok := methods[alg]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedAlgorithm, alg)
+	}
+	return m, nil
+}
+
+func (m hmacMethod) sign(signingInput string, key interface{}) ([]byte, error) {
+	secret, ok := key.([]byte)
+	// A PEM public key as HMAC secret is the classic algorithm confusion.
+	if !ok || len(secret) == 0 || bytes.Contains(secret, []byte("-----BEGIN")) {
+		return nil, fmt.Errorf("%w: hmac requires a []byte secret", ErrInvalidKey)
+	}
+	mac := hmac.New(m.hash.New, secret)
+	mac.Write([]byte(signingInput))
+	return mac.Sum(nil), nil
+}
+
+func (m hmacMethod) verify(signingInput string, sig []byte, key interface{}) error {
+	expected, err := m.sign(signingInput, key)
+	if err != nil {
+		return err
+	}
+	if !hmac.Equal(sig, expected) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func (m rsaMethod) sign(signingInput string, key interface{}) ([]byte, error) {
+	var prv *rsa.PrivateKey
+	switch key := key.(type) {
+	case *rsa.PrivateKey:
+		prv = key
+	case []byte:
+		var err error
+		if prv, err = cryptorsa.ParsePrivateKey(key); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidKey, err)
+		}
+	default:
+		return nil, fmt.Errorf("%w: %T is not an rsa private key", ErrInvalidKey, key)
+	}
+	if prv.N.BitLen() < minRSABits {
+		return nil, fmt.Errorf("%w: rsa key is smaller than %d bits", ErrInvalidKey, minRSABits)
+	}
+	digest := hashInput(m.hash, signingInput)
+	if m.pss {
+		return rsa.SignPSS(rand.Reader, prv, m.hash, digest, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+	}
+	return rsa.SignPKCS1v15(rand.Reader, prv, m.hash, digest)
+}
+
+func (m rsaMethod) verify(signingInput string, sig []byte, key interface{}) error {
+	var pub *rsa.PublicKey
+	switch key := key.(type) {
+	case *rsa.PublicKey:
+		pub = key
+	case *rsa.PrivateKey:
+		pub = &key.PublicKey
+	case []byte:
+		var err error
+		if pub, err = cryptorsa.ParsePublicKey(key); err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidKey, err)
+		}
+	default:
+		return fmt.Errorf("%w: %T is not an rsa public key", ErrInvalidKey, key)
+	}
+	if pub.N.BitLen() < minRSABits {
+		return fmt.Errorf("%w: rsa key is smaller than %d bits", ErrInvalidKey, minRSABits)
+	}
+	digest := hashInput(m.hash, signingInput)
+	var err error
+	if m.pss {
+		err = rsa.VerifyPSS(pub, m.hash, digest, sig, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthAuto})
+	} else {
+		err = rsa.VerifyPKCS1v15(pub, m.hash, digest, sig)
+	}
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func (m ecdsaMethod) sign(signingInput string, key interface{}) ([]byte, error) {
+	var prv *ecc.PrivateKey
+	switch key := key.(type) {
+	case *ecc.PrivateKey:
+		prv = key
+	case *ecdsa.PrivateKey:
+		prv = ecc.ImportECDSA(key)
+	case []byte:
+		var err error
+		if prv, err = ecc.ParsePrivateKey(key); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidKey, err)
+		}
+	default:
+		return nil, fmt.Errorf("%w: %T is not an ecdsa private key", ErrInvalidKey, key)
+	}
+	if prv.Curve != m.curve {
+		return nil, fmt.Errorf("%w: %s requires curve %s", ErrInvalidKey, m.name(), m.curve.Params().Name)
+	}
+	return ecc.SignP1363(prv, []byte(signingInput), m.hash.New())
+}
+
+func (m ecdsaMethod) verify(signingInput string, sig []byte, key interface{}) error {
+	var pub *ecc.PublicKey
+	switch key := key.(type) {
+	case *ecc.PublicKey:
+		pub = key
+	case *ecc.PrivateKey:
+		pub = &key.PublicKey
+	case *ecdsa.PublicKey:
+		pub = ecc.ImportECDSAPublic(key)
+	case *ecdsa.PrivateKey:
+		pub = ecc.ImportECDSAPublic(&key.PublicKey)
+	case []byte:
+		var err error
+		if pub, err = ecc.ParsePublicKey(key); err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidKey, err)
+		}
+	default:
+		return fmt.Errorf("%w: %T is not an ecdsa public key", ErrInvalidKey, key)
+	}
+	if pub.Curve != m.curve {
+		return fmt.Errorf("%w: %s requires curve %s", ErrInvalidKey, m.name(), m.curve.Params().Name)
+	}
+	if !ecc.VerifyP1363(pub, []byte(signingInput), sig, m.hash.New()) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func (m ecdsaMethod) name() string {
+	return fmt.Sprintf("ES%d", m.hash.Size()*8)
+}
+
+func (eddsaMethod) sign(signingInput string, key interface{}) ([]byte, error) {
+	var prv ed25519.PrivateKey
+	switch key := key.(type) {
+	case ed25519.PrivateKey:
+		prv = key
+	case []byte:
+		var err error
+		if prv, err = cryptoed25519.ParsePrivateKey(key); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidKey, err)
+		}
+	default:
+		return nil, fmt.Errorf("%w: %T is not an ed25519 private key", ErrInvalidKey, key)
+	}
+	if len(prv) != ed25519.PrivateKeySize {
+		return nil, ErrInvalidKey
+	}
+	return ed25519.Sign(prv, []byte(signingInput)), nil
+}
+
+func (eddsaMethod) verify(signingInput string, sig []byte, key interface{}) error {
+	var pub ed25519.PublicKey
+	switch key := key.(type) {
+	case ed25519.PublicKey:
+		pub = key
+	case ed25519.PrivateKey:
+		if len(key) != ed25519.PrivateKeySize {
+			return ErrInvalidKey
+		}
+		pub = key.Public().(ed25519.PublicKey)
+	case []byte:
+		var err error
+		if pub, err = cryptoed25519.ParsePublicKey(key); err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidKey, err)
+		}
+	default:
+		return fmt.Errorf("%w: %T is not an ed25519 public key", ErrInvalidKey, key)
+	}
+	if len(pub) != ed25519.PublicKeySize {
+		return ErrInvalidKey
+	}
+	if !ed25519.Verify(pub, []byte(signingInput), sig) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func hashInput(hash crypto.Hash, signingInput string) []byte {
+	h := hash.New()
+	h.Write([]byte(signingInput))
+	return h.Sum(nil)
+}
@@ -0,0 +1,351 @@
+package jwt
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/trumanwong/cryptogo/jwk"
+)
+
+// maxKeySetSize bounds the JWKS documents read by the fetchers.
+const maxKeySetSize = 1 << 20
+
+// KeySource provides the JWK Set tokens are verified with. refresh asks for
+// a fresh copy, when a token names a kid that is not in the current set.
+type KeySource interface {
+	KeySet(refresh bool) (*jwk.Set, error)
+}
+
+type staticKeySet struct {
+	set *jwk.Set
+}
+
+// StaticKeySet is a KeySource of a fixed JWK Set.
+func StaticKeySet(set *jwk.Set) KeySource {
+	return staticKeySet{set: set}
+}
+
+// ParseKeySet is a KeySource of a fixed JWKS document.
+func ParseKeySet(data []byte) (KeySource, error) {
+	set, err := jwk.ParseSet(data)
+	if err != nil {
+		return nil, err
+	}
+	return StaticKeySet(set), nil
+}
+
+func (s staticKeySet) KeySet(bool) (*jwk.Set, error) {
+	return s.set, nil
+}
+
+// Fetcher returns a JWKS document.
+type Fetcher func() ([]byte, error)
+
+// FileFetcher reads the JWKS document at path, which lets a mounted file be
+// rotated without a restart when used with NewCachedKeySet.
+func FileFetcher(path string) Fetcher {
+	return func() ([]byte, error) {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		return io.ReadAll(io.LimitReader(f, maxKeySetSize))
+	}
+}
+
+// HTTPFetcher gets the JWKS document at url, such as the jwks_uri of an
+// OpenID provider. A nil client is an http.Client with a 10 second timeout.
+func HTTPFetcher(client *http.Client, url string) Fetcher {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return func() ([]byte, error) {
+		resp, err := client.Get(url)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("jwt: fetch %s: %s", url, resp.Status)
+		}
+		return io.ReadAll(io.LimitReader(resp.Body, maxKeySetSize))
+	}
+}
+
+// CacheOptions configures a CachedKeySet, nil means the defaults.
+type CacheOptions struct {
+	// TTL is how long a fetched set is used, 1 hour if zero.
+	TTL time.Duration
+	// MinRefreshInterval is the least time between fetches, so tokens with
+	// random kids or a failing key server cannot hammer it. 1 minute if
+	// zero.
+	MinRefreshInterval time.Duration
+	// Now returns the current time, time.Now if nil.
+	Now func() time.Time
+}
+
+// CachedKeySet is a KeySource that fetches the JWK Set on first use, when
+// the TTL has passed and on refresh. If a fetch fails after the first one,
+// the previous set keeps being used and the error is returned only when no
+// set was ever fetched. Fetches, failed or not, are at least
+// MinRefreshInterval apart, so a key server that is down is neither
+// hammered nor waited on by every call. Only one fetch runs at a time;
+// other calls meanwhile get the previous set, or wait for the first one.
+type CachedKeySet struct {
+	fetch Fetcher
+	opts  CacheOptions
+
+	mu        sync.Mutex
+	set       *jwk.Set
+	err       error
+	fetched   time.Time
+	attempted time.Time
+	// fetching is closed when the fetch in flight, if any, is done.
+	fetching chan struct{}
+}
+
+// NewCachedKeySet creates a CachedKeySet fetching with fetch.
+func NewCachedKeySet(fetch Fetcher, opts *CacheOptions) *CachedKeySet {
+	c := &CachedKeySet{fetch: fetch}
+	if opts != nil {
+		c.opts = *opts
+	}
+	if c.opts.TTL <= 0 {
+		c.opts.TTL = time.Hour
+	}
+	if c.opts.MinRefreshInterval <= 0 {
+		c.opts.MinRefreshInterval = time.Minute
+	}
+	if c.opts.Now == nil {
+		c.opts.Now = time.Now
+	}
+	return c
+}
+
+// KeySet returns the cached set, fetching it again if needed. The lock is
+// not held during the fetch, so verifications are not blocked by a slow
+// key server.
+func (c *CachedKeySet) KeySet(refresh bool) (*jwk.Set, error) {
+	c.mu.Lock()
+	now := c.opts.Now()
+	if c.set != nil && !refresh && now.Sub(c.fetched) < c.opts.TTL {
+		defer c.mu.Unlock()
+		return c.set, nil
+	}
+	if fetching := c.fetching; fetching != nil {
+		if c.set != nil {
+			defer c.mu.Unlock()
+			return c.set, nil
+		}
+		c.mu.Unlock()
+		<-fetching
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return c.result()
+	}
+	if !c.attempted.IsZero() && now.Sub(c.attempted) < c.opts.MinRefreshInterval {
+		defer c.mu.Unlock()
+		return c.result()
+	}
+	c.attempted = now
+	fetching := make(chan struct{})
+	c.fetching = fetching
+	c.mu.Unlock()
+
+	var set *jwk.Set
+	data, err := c.fetch()
+	if err == nil {
+		set, err = jwk.ParseSet(data)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err == nil {
+		c.set, c.fetched = set, now
+	}
+	c.err = err
+	c.fetching = nil
+	close(fetching)
+	return c.result()
+}
+
+// result returns the cached set, or the last error if there is none.
+func (c *CachedKeySet) result() (*jwk.Set, error) {
+	if c.set == nil {
+		return nil, c.err
+	}
+	return c.set, nil
+}
+
+// Verifier verifies tokens with the key named by their kid header.
+type Verifier struct {
+	// Keys is where the keys are looked up.
+	Keys KeySource
+	// Algorithms is the allow-list passed to Verify.
+	Algorithms []Algorithm
+	// Validator is the claim policy, nil only checks exp, nbf and iat.
+	Validator *Validator
+}
+
+// Verify looks up the key of the token's kid, refreshing the key source
+// once if it is unknown, then verifies the signature and the claims. A
+// token without kid is only accepted if the set holds a single key. Keys
+// whose "alg" or "use" do not match the token are rejected.
+func (v *Verifier) Verify(token string) (*Header, []byte, error) {
+	header, err := ParseHeader(token)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !isAllowed(header.Alg, v.Algorithms) {
+		return nil, nil, fmt.Errorf("%w: %s", ErrAlgorithmNotAllowed, header.Alg)
+	}
+	k, err := v.lookup(header.Kid, false)
+	if errors.Is(err, jwk.ErrKeyNotFound) {
+		k, err = v.lookup(header.Kid, true)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if (k.Alg != "" && Algorithm(k.Alg) != header.Alg) || (k.Use != "" && k.Use != "sig") {
+		return nil, nil, fmt.Errorf("%w: key %q is not for %s signatures", ErrInvalidKey, k.Kid, header.Alg)
+	}
+	key, err := k.Key()
+	if err != nil {
+		return nil, nil, err
+	}
+	_, payload, err := Verify(token, key, v.Algorithms...)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = v.Validator.Validate(payload); err != nil {
+		return nil, nil, err
+	}
+	return header, payload, nil
+}
+
+func (v *Verifier) lookup(kid string, refresh bool) (*jwk.Key, error) {
+	set, err := v.Keys.KeySet(refresh)
+	if err != nil {
+		return nil, err
+	}
+	if kid == "" {
+		if len(set.Keys) != 1 {
+			return nil, fmt.Errorf("%w: token has no kid", jwk.ErrKeyNotFound)
+		}
+		return set.Keys[0], nil
+	}
+	k, err := set.Lookup(kid)
+	if err != nil {
+		return nil, fmt.Errorf("%w: kid %q", err, kid)
+	}
+	return k, nil
+}
+
+// VerifyClaims verifies token with v and decodes its claims into T.
+func VerifyClaims[T any](v *Verifier, token string) (*T, error) {
+	_, payload, err := v.Verify(token)
+	if err != nil {
+		return nil, err
+	}
+	claims := new(T)
+	if err = unmarshal(payload, claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// KeyRing holds the signing keys of an issuer. Tokens are signed with the
+// current key and stamped with its kid, earlier keys stay in the ring so
+// tokens they signed keep verifying until they are removed.
+type KeyRing struct {
+	mu      sync.RWMutex
+	keys    []*jwk.Key
+	current *jwk.Key
+}
+
+// NewKeyRing creates an empty KeyRing.
+func NewKeyRing() *KeyRing {
+	return new(KeyRing)
+}
+
+// Rotate adds a private key, as accepted by jwk.New, for alg and makes it
+// the current signing key. Its kid is the RFC 7638 thumbprint.
+func (r *KeyRing) Rotate(key interface{}, alg Algorithm) (*jwk.Key, error) {
+	k, err := jwk.New(key)
+	if err != nil {
+		return nil, err
+	}
+	if !k.IsPrivate() {
+		return nil, fmt.Errorf("%w: signing requires a private key", ErrInvalidKey)
+	}
+	// check the key fits the algorithm before it signs anything
+	if _, err = signPayload(&Header{Alg: alg}, nil, key); err != nil {
+		return nil, err
+	}
+	k.Alg, k.Use = string(alg), "sig"
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.keys = append(r.keys, k)
+	r.current = k
+	return k, nil
+}
+
+// Remove drops the key with kid, the current key cannot be removed.
+func (r *KeyRing) Remove(kid string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.current != nil && r.current.Kid == kid {
+		return errors.New("jwt: cannot remove the current key")
+	}
+	for i, k := range r.keys {
+		if k.Kid == kid {
+			r.keys = append(r.keys[:i:i], r.keys[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: kid %q", jwk.ErrKeyNotFound, kid)
+}
+
+// Current returns the current signing key, nil if the ring is empty.
+func (r *KeyRing) Current() *jwk.Key {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.current
+}
+
+// Sign signs claims with the current key, the header carries its kid.
+func (r *KeyRing) Sign(claims interface{}) (string, error) {
+	k := r.Current()
+	if k == nil {
+		return "", fmt.Errorf("%w: key ring is empty", ErrInvalidKey)
+	}
+	key, err := k.Key()
+	if err != nil {
+		return "", err
+	}
+	return SignWithHeader(&Header{Alg: Algorithm(k.Alg), Typ: "JWT", Kid: k.Kid}, claims, key)
+}
+
+// JWKS returns the public keys of the ring to publish at the jwks_uri.
+func (r *KeyRing) JWKS() *jwk.Set {
+	return r.set().Public()
+}
+
+// KeySet makes the ring a KeySource, so the issuer can verify its own
+// tokens, including ones signed with symmetric keys.
+func (r *KeyRing) KeySet(bool) (*jwk.Set, error) {
+	return r.set(), nil
+}
+
+func (r *KeyRing) set() *jwk.Set {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return jwk.NewSet(append([]*jwk.Key(nil), r.keys...)...)
+}
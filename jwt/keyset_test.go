@@ -0,0 +1,332 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trumanwong/cryptogo/jwk"
+)
+
+func ExampleVerifier() {
+	ring := NewKeyRing()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if _, err = ring.Rotate(key, EdDSA); err != nil {
+		log.Fatal(err)
+	}
+	token, err := ring.Sign(map[string]string{"sub": "trumanwong"})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// the verifier only sees the published JWKS
+	jwks, err := json.Marshal(ring.JWKS())
+	if err != nil {
+		log.Fatal(err)
+	}
+	keys, err := ParseKeySet(jwks)
+	if err != nil {
+		log.Fatal(err)
+	}
+	v := &Verifier{Keys: keys, Algorithms: []Algorithm{EdDSA}}
+	claims, err := VerifyClaims[RegisteredClaims](v, token)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(claims.Subject)
+	// Output: trumanwong
+}
+
+func TestKeyRing(t *testing.T) {
+	ring := NewKeyRing()
+	_, err := ring.Sign(nil)
+	assert.ErrorIs(t, err, ErrInvalidKey)
+
+	p256, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	old, err := ring.Rotate(p256, ES256)
+	assert.NoError(t, err)
+	oldToken, err := ring.Sign(map[string]string{"sub": "old"})
+	assert.NoError(t, err)
+	header, err := ParseHeader(oldToken)
+	assert.NoError(t, err)
+	assert.Equal(t, &Header{Alg: ES256, Typ: "JWT", Kid: old.Kid}, header)
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	current, err := ring.Rotate(edKey, EdDSA)
+	assert.NoError(t, err)
+	assert.Equal(t, current, ring.Current())
+	newToken, err := ring.Sign(map[string]string{"sub": "new"})
+	assert.NoError(t, err)
+
+	jwks := ring.JWKS()
+	assert.Len(t, jwks.Keys, 2)
+	for _, k := range jwks.Keys {
+		assert.False(t, k.IsPrivate())
+		assert.Equal(t, "sig", k.Use)
+	}
+
+	// both generations verify until the old key is removed
+	v := &Verifier{Keys: StaticKeySet(jwks), Algorithms: []Algorithm{ES256, EdDSA}}
+	_, _, err = v.Verify(oldToken)
+	assert.NoError(t, err)
+	_, _, err = v.Verify(newToken)
+	assert.NoError(t, err)
+
+	assert.Error(t, ring.Remove(current.Kid))
+	assert.NoError(t, ring.Remove(old.Kid))
+	assert.ErrorIs(t, ring.Remove(old.Kid), jwk.ErrKeyNotFound)
+	v.Keys = ring
+	_, _, err = v.Verify(oldToken)
+	assert.ErrorIs(t, err, jwk.ErrKeyNotFound)
+	_, _, err = v.Verify(newToken)
+	assert.NoError(t, err)
+
+	// the key must fit the algorithm, and public keys cannot sign
+	_, err = ring.Rotate(p256, ES384)
+	assert.ErrorIs(t, err, ErrInvalidKey)
+	_, err = ring.Rotate(&p256.PublicKey, ES256)
+	assert.ErrorIs(t, err, ErrInvalidKey)
+	assert.Equal(t, current, ring.Current())
+}
+
+func TestVerifier(t *testing.T) {
+	p256, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	k, err := jwk.New(&p256.PublicKey)
+	assert.NoError(t, err)
+	set := jwk.NewSet(k)
+	now := time.Unix(1700000000, 0)
+
+	sign := func(kid string, alg Algorithm, claims interface{}) string {
+		token, err := SignWithHeader(&Header{Alg: alg, Kid: kid}, claims, p256)
+		assert.NoError(t, err)
+		return token
+	}
+	v := &Verifier{
+		Keys:       StaticKeySet(set),
+		Algorithms: []Algorithm{ES256},
+		Validator:  &Validator{Audience: "api", Now: func() time.Time { return now }},
+	}
+
+	_, _, err = v.Verify(sign(k.Kid, ES256, map[string]string{"aud": "api"}))
+	assert.NoError(t, err)
+	// a single key is used for tokens without kid
+	_, _, err = v.Verify(sign("", ES256, map[string]string{"aud": "api"}))
+	assert.NoError(t, err)
+	_, _, err = v.Verify(sign("other", ES256, map[string]string{"aud": "api"}))
+	assert.ErrorIs(t, err, jwk.ErrKeyNotFound)
+	_, _, err = v.Verify(sign(k.Kid, ES256, map[string]string{"aud": "web"}))
+	assert.ErrorIs(t, err, ErrInvalidAudience)
+
+	// the algorithm allow-list is checked before any key lookup
+	token, err := SignWithHeader(&Header{Alg: HS256, Kid: k.Kid}, nil, []byte("key"))
+	assert.NoError(t, err)
+	_, _, err = v.Verify(token)
+	assert.ErrorIs(t, err, ErrAlgorithmNotAllowed)
+
+	// keys pinned to another alg or use are rejected
+	k.Alg = string(ES384)
+	_, _, err = v.Verify(sign(k.Kid, ES256, map[string]string{"aud": "api"}))
+	assert.ErrorIs(t, err, ErrInvalidKey)
+	k.Alg, k.Use = "", "enc"
+	_, _, err = v.Verify(sign(k.Kid, ES256, map[string]string{"aud": "api"}))
+	assert.ErrorIs(t, err, ErrInvalidKey)
+}
+
+func TestCachedKeySet(t *testing.T) {
+	ring := NewKeyRing()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	_, err = ring.Rotate(key, EdDSA)
+	assert.NoError(t, err)
+
+	var fetches, failing int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fetches, 1)
+		if atomic.LoadInt32(&failing) == 1 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		json.NewEncoder(w).Encode(ring.JWKS())
+	}))
+	defer server.Close()
+
+	now := time.Unix(1700000000, 0)
+	keys := NewCachedKeySet(HTTPFetcher(server.Client(), server.URL), &CacheOptions{
+		TTL:                time.Hour,
+		MinRefreshInterval: time.Minute,
+		Now:                func() time.Time { return now },
+	})
+	v := &Verifier{Keys: keys, Algorithms: []Algorithm{EdDSA}}
+	verify := func() error {
+		token, err := ring.Sign(map[string]string{"sub": "trumanwong"})
+		assert.NoError(t, err)
+		_, _, err = v.Verify(token)
+		return err
+	}
+
+	assert.NoError(t, verify())
+	assert.NoError(t, verify())
+	assert.Equal(t, int32(1), atomic.LoadInt32(&fetches))
+
+	// an unknown kid refreshes, but not more than once per interval
+	_, key, err = ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	_, err = ring.Rotate(key, EdDSA)
+	assert.NoError(t, err)
+	now = now.Add(30 * time.Second)
+	assert.ErrorIs(t, verify(), jwk.ErrKeyNotFound)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&fetches))
+	now = now.Add(time.Minute)
+	assert.NoError(t, verify())
+	assert.Equal(t, int32(2), atomic.LoadInt32(&fetches))
+
+	// after the TTL the set is fetched again, a failing server keeps the
+	// previous set in use
+	atomic.StoreInt32(&failing, 1)
+	now = now.Add(2 * time.Hour)
+	assert.NoError(t, verify())
+	assert.Equal(t, int32(3), atomic.LoadInt32(&fetches))
+
+	_, err = NewCachedKeySet(HTTPFetcher(server.Client(), server.URL), nil).KeySet(false)
+	assert.Error(t, err)
+}
+
+func TestCachedKeySetBackoff(t *testing.T) {
+	set := jwk.NewSet()
+	data, err := json.Marshal(set)
+	assert.NoError(t, err)
+	var fetches int
+	failing := true
+	fetch := func() ([]byte, error) {
+		fetches++
+		if failing {
+			return nil, errors.New("unavailable")
+		}
+		return data, nil
+	}
+	now := time.Unix(1700000000, 0)
+	keys := NewCachedKeySet(fetch, &CacheOptions{
+		TTL:                time.Hour,
+		MinRefreshInterval: time.Minute,
+		Now:                func() time.Time { return now },
+	})
+
+	// a failed first fetch is not retried within the interval
+	for i := 0; i < 3; i++ {
+		_, err = keys.KeySet(i > 0)
+		assert.EqualError(t, err, "unavailable")
+	}
+	assert.Equal(t, 1, fetches)
+	now = now.Add(time.Minute)
+	failing = false
+	got, err := keys.KeySet(false)
+	assert.NoError(t, err)
+	assert.Equal(t, set, got)
+	assert.Equal(t, 2, fetches)
+
+	// once the TTL has passed a failing server is tried once per interval
+	// and the previous set is used meanwhile
+	failing = true
+	now = now.Add(2 * time.Hour)
+	for i := 0; i < 3; i++ {
+		got, err = keys.KeySet(i > 0)
+		assert.NoError(t, err)
+		assert.Equal(t, set, got)
+		now = now.Add(10 * time.Second)
+	}
+	assert.Equal(t, 3, fetches)
+	now = now.Add(time.Minute)
+	_, err = keys.KeySet(false)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, fetches)
+}
+
+func TestCachedKeySetConcurrent(t *testing.T) {
+	set := jwk.NewSet()
+	data, err := json.Marshal(set)
+	assert.NoError(t, err)
+	var fetches int32
+	release := make(chan struct{})
+	fetch := func() ([]byte, error) {
+		atomic.AddInt32(&fetches, 1)
+		<-release
+		return data, nil
+	}
+	now := time.Unix(1700000000, 0)
+	keys := NewCachedKeySet(fetch, &CacheOptions{Now: func() time.Time { return now }})
+
+	// callers without a set wait for the single first fetch
+	results := make(chan error, 3)
+	for i := 0; i < 3; i++ {
+		go func() {
+			_, err := keys.KeySet(false)
+			results <- err
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, <-results)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&fetches))
+
+	// a slow refresh does not block the callers served from the cache
+	release = make(chan struct{})
+	now = now.Add(2 * time.Hour)
+	go func() {
+		_, err := keys.KeySet(false)
+		results <- err
+	}()
+	for atomic.LoadInt32(&fetches) != 2 {
+		time.Sleep(time.Millisecond)
+	}
+	got, err := keys.KeySet(true)
+	assert.NoError(t, err)
+	assert.Equal(t, set, got)
+	close(release)
+	assert.NoError(t, <-results)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&fetches))
+}
+
+func TestFileFetcher(t *testing.T) {
+	ring := NewKeyRing()
+	_, err := ring.Rotate([]byte("0123456789abcdef0123456789abcdef"), HS256)
+	assert.NoError(t, err)
+	token, err := ring.Sign(map[string]string{"sub": "trumanwong"})
+	assert.NoError(t, err)
+
+	// symmetric keys are not published, so write the full set
+	set, err := ring.KeySet(false)
+	assert.NoError(t, err)
+	data, err := json.Marshal(set)
+	assert.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	assert.NoError(t, os.WriteFile(path, data, 0600))
+
+	v := &Verifier{Keys: NewCachedKeySet(FileFetcher(path), nil), Algorithms: []Algorithm{HS256}}
+	_, payload, err := v.Verify(token)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"sub":"trumanwong"}`, string(payload))
+
+	_, err = NewCachedKeySet(FileFetcher(filepath.Join(t.TempDir(), "missing")), nil).KeySet(false)
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+}
@@ -1,9 +1,14 @@
 package cryptogo
 
 import (
+	"crypto/ed25519"
+	"crypto/rand"
 	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/trumanwong/cryptogo/jwt"
 	"log"
 	"testing"
+	"time"
 )
 
 func TestJwtEncrypt(t *testing.T) {
@@ -53,3 +58,48 @@ func ExampleJwtDecrypt() {
 	fmt.Println(claims["username"])
 	// Output: trumanwong
 }
+
+type jwtClaims struct {
+	jwt.RegisteredClaims
+	Username string `json:"username"`
+}
+
+func ExampleJwtEncryptClaims() {
+	key := []byte("key")
+	tokenString, err := JwtEncryptClaims(key, jwtClaims{
+		RegisteredClaims: jwt.RegisteredClaims{Issuer: "cryptogo"},
+		Username:         "trumanwong",
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	claims, err := JwtDecryptClaims[jwtClaims](tokenString, key, &jwt.Validator{Issuer: "cryptogo"})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(claims.Username)
+	// Output: trumanwong
+}
+
+func TestJwtDecryptClaims(t *testing.T) {
+	key := []byte("key")
+	tokenString, err := JwtEncrypt(key, map[string]interface{}{"exp": time.Now().Add(-time.Minute).Unix()})
+	assert.NoError(t, err)
+	_, err = JwtDecrypt(tokenString, key)
+	assert.ErrorIs(t, err, jwt.ErrTokenExpired)
+
+	tokenString, err = JwtEncryptClaims(key, jwtClaims{Username: "trumanwong"})
+	assert.NoError(t, err)
+	_, err = JwtDecryptClaims[jwtClaims](tokenString, key, &jwt.Validator{Issuer: "cryptogo"})
+	assert.ErrorIs(t, err, jwt.ErrInvalidIssuer)
+	_, err = JwtDecryptClaims[jwtClaims](tokenString, []byte("other"), nil)
+	assert.ErrorIs(t, err, jwt.ErrInvalidSignature)
+
+	// only HMAC tokens are accepted
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	tokenString, err = jwt.Sign(map[string]interface{}{}, jwt.EdDSA, edKey)
+	assert.NoError(t, err)
+	_, err = JwtDecrypt(tokenString, key)
+	assert.ErrorIs(t, err, jwt.ErrAlgorithmNotAllowed)
+}
@@ -0,0 +1,112 @@
+// Package openssh converts RSA, ECDSA and Ed25519 keys to and from the
+// OpenSSH authorized_keys and "OPENSSH PRIVATE KEY" formats.
+package openssh
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/pem"
+	"errors"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// MarshalAuthorizedKey encode a *rsa.PublicKey, *ecdsa.PublicKey (P-256,
+// P-384 or P-521) or ed25519.PublicKey to a single authorized_keys line such
+// as "ssh-ed25519 AAAA... comment", terminated by a newline.
+func MarshalAuthorizedKey(pub crypto.PublicKey, comment string) ([]byte, error) {
+	sshPub, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		return nil, err
+	}
+	line := bytes.TrimSuffix(ssh.MarshalAuthorizedKey(sshPub), []byte("\n"))
+	if comment != "" {
+		line = append(append(line, ' '), comment...)
+	}
+	return append(line, '\n'), nil
+}
+
+// ParseAuthorizedKey parse the first key of an authorized_keys line or
+// file, return *rsa.PublicKey, *ecdsa.PublicKey or ed25519.PublicKey and the
+// comment. Options before the key type are accepted and ignored.
+func ParseAuthorizedKey(in []byte) (crypto.PublicKey, string, error) {
+	sshPub, comment, _, _, err := ssh.ParseAuthorizedKey(in)
+	if err != nil {
+		return nil, "", err
+	}
+	pub, err := cryptoPublicKey(sshPub)
+	if err != nil {
+		return nil, "", err
+	}
+	return pub, comment, nil
+}
+
+// MarshalPrivateKey encode a *rsa.PrivateKey, *ecdsa.PrivateKey or
+// ed25519.PrivateKey to an "OPENSSH PRIVATE KEY" pem, encrypted with
+// passphrase (aes256-ctr, bcrypt KDF) unless passphrase is empty.
+func MarshalPrivateKey(priv crypto.PrivateKey, comment string, passphrase []byte) ([]byte, error) {
+	var block *pem.Block
+	var err error
+	if len(passphrase) == 0 {
+		block, err = ssh.MarshalPrivateKey(priv, comment)
+	} else {
+		block, err = ssh.MarshalPrivateKeyWithPassphrase(priv, comment, passphrase)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(block), nil
+}
+
+// ParsePrivateKey parse an "OPENSSH PRIVATE KEY" pem, decrypting it with
+// passphrase when it is encrypted. Return *rsa.PrivateKey, *ecdsa.PrivateKey
+// or ed25519.PrivateKey.
+func ParsePrivateKey(pemBytes, passphrase []byte) (crypto.PrivateKey, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil || block.Type != "OPENSSH PRIVATE KEY" {
+		return nil, errors.New("key is invalid format")
+	}
+
+	var key interface{}
+	var err error
+	if len(passphrase) == 0 {
+		key, err = ssh.ParseRawPrivateKey(pemBytes)
+	} else {
+		key, err = ssh.ParseRawPrivateKeyWithPassphrase(pemBytes, passphrase)
+	}
+	if err != nil {
+		return nil, err
+	}
+	switch k := key.(type) {
+	case *rsa.PrivateKey, *ecdsa.PrivateKey, ed25519.PrivateKey:
+		return k, nil
+	case *ed25519.PrivateKey:
+		return *k, nil
+	}
+	return nil, errors.New("key is invalid private key")
+}
+
+// Fingerprint return the SHA256 fingerprint of a public key as
+// ssh-keygen -l prints it, e.g. "SHA256:nThbg6kXUpJWGl7E1IGOCspRomTxdCARLviKw6E5SY8".
+func Fingerprint(pub crypto.PublicKey) (string, error) {
+	sshPub, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		return "", err
+	}
+	return ssh.FingerprintSHA256(sshPub), nil
+}
+
+func cryptoPublicKey(sshPub ssh.PublicKey) (crypto.PublicKey, error) {
+	cryptoPub, ok := sshPub.(ssh.CryptoPublicKey)
+	if !ok {
+		return nil, errors.New("key is invalid public key")
+	}
+	switch pub := cryptoPub.CryptoPublicKey().(type) {
+	case *rsa.PublicKey, *ecdsa.PublicKey, ed25519.PublicKey:
+		return pub, nil
+	}
+	return nil, errors.New("unsupported public key type " + sshPub.Type())
+}
@@ -0,0 +1,132 @@
+// Package otp implements HOTP (RFC 4226) and TOTP (RFC 6238) one-time
+// passwords and the otpauth:// key URIs read by authenticator apps.
+package otp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+)
+
+// Algorithm is the HMAC hash of a one-time password, named as in otpauth
+// URIs.
+type Algorithm string
+
+const (
+	SHA1   Algorithm = "SHA1"
+	SHA256 Algorithm = "SHA256"
+	SHA512 Algorithm = "SHA512"
+)
+
+const (
+	// DefaultDigits is the code length used when Digits is zero.
+	DefaultDigits = 6
+	// DefaultSecretSize is the 160 bit secret size recommended by RFC 4226.
+	DefaultSecretSize = 20
+)
+
+var (
+	ErrInvalidSecret    = errors.New("otp: invalid secret")
+	ErrInvalidDigits    = errors.New("otp: digits must be between 6 and 8")
+	ErrInvalidAlgorithm = errors.New("otp: unsupported algorithm")
+	ErrInvalidCode      = errors.New("otp: invalid code")
+	ErrReplayedCode     = errors.New("otp: code already used")
+)
+
+var hashes = map[Algorithm]func() hash.Hash{
+	SHA1:   sha1.New,
+	SHA256: sha256.New,
+	SHA512: sha512.New,
+}
+
+var powers = [...]uint32{6: 1e6, 7: 1e7, 8: 1e8}
+
+// GenerateSecret returns a random secret of size bytes, DefaultSecretSize
+// if size is zero.
+func GenerateSecret(size int) ([]byte, error) {
+	if size == 0 {
+		size = DefaultSecretSize
+	}
+	secret := make([]byte, size)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
+// Generate computes the RFC 4226 code of counter with secret, digits (6 to
+// 8, DefaultDigits if zero) and alg (SHA1 if empty).
+func Generate(secret []byte, counter uint64, digits int, alg Algorithm) (string, error) {
+	if len(secret) == 0 {
+		return "", ErrInvalidSecret
+	}
+	if digits == 0 {
+		digits = DefaultDigits
+	}
+	if digits < 6 || digits > 8 {
+		return "", ErrInvalidDigits
+	}
+	if alg == "" {
+		alg = SHA1
+	}
+	h, ok := hashes[alg]
+	if !ok {
+		return "", fmt.Errorf("%w: %q", ErrInvalidAlgorithm, alg)
+	}
+
+	mac := hmac.New(h, secret)
+	mac.Write(binary.BigEndian.AppendUint64(nil, counter))
+	sum := mac.Sum(nil)
+	// dynamic truncation
+	offset := sum[len(sum)-1] & 0x0f
+	code := binary.BigEndian.Uint32(sum[offset:]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", digits, code%powers[digits]), nil
+}
+
+// HOTP generates and validates counter based codes (RFC 4226).
+type HOTP struct {
+	Secret    []byte
+	Digits    int
+	Algorithm Algorithm
+	// LookAhead is how many counters after the expected one Validate
+	// accepts, to resynchronize with a token that was used without the
+	// server seeing it.
+	LookAhead uint
+}
+
+// Generate returns the code of counter.
+func (h *HOTP) Generate(counter uint64) (string, error) {
+	return Generate(h.Secret, counter, h.Digits, h.Algorithm)
+}
+
+// Validate checks code against counter and the next LookAhead counters,
+// return the counter to store for the next validation (the matched one plus
+// one). A code is accepted once as long as the returned counter is stored.
+func (h *HOTP) Validate(code string, counter uint64) (uint64, error) {
+	for i := uint64(0); i <= uint64(h.LookAhead); i++ {
+		ok, err := match(h.Secret, counter+i, h.Digits, h.Algorithm, code)
+		if err != nil {
+			return counter, err
+		}
+		if ok {
+			return counter + i + 1, nil
+		}
+	}
+	return counter, ErrInvalidCode
+}
+
+// match compares code to the code of counter in constant time.
+func match(secret []byte, counter uint64, digits int, alg Algorithm, code string) (bool, error) {
+	expected, err := Generate(secret, counter, digits, alg)
+	if err != nil {
+		return false, err
+	}
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1, nil
+}
@@ -0,0 +1,89 @@
+package otp
+
+import (
+	"fmt"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// RFC 4226, appendix D
+var rfc4226Secret = []byte("12345678901234567890")
+
+func ExampleGenerate() {
+	code, err := Generate([]byte("12345678901234567890"), 1, 6, SHA1)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(code)
+	// Output: 287082
+}
+
+func TestGenerate(t *testing.T) {
+	for counter, expected := range []string{
+		"755224", "287082", "359152", "969429", "338314",
+		"254676", "287922", "162583", "399871", "520489",
+	} {
+		code, err := Generate(rfc4226Secret, uint64(counter), 0, "")
+		assert.NoError(t, err)
+		assert.Equal(t, expected, code)
+	}
+
+	code, err := Generate(rfc4226Secret, 0, 8, SHA1)
+	assert.NoError(t, err)
+	assert.Equal(t, "84755224", code)
+	code, err = Generate(rfc4226Secret, 0, 7, SHA1)
+	assert.NoError(t, err)
+	assert.Len(t, code, 7)
+
+	_, err = Generate(nil, 0, 6, SHA1)
+	assert.ErrorIs(t, err, ErrInvalidSecret)
+	_, err = Generate(rfc4226Secret, 0, 5, SHA1)
+	assert.ErrorIs(t, err, ErrInvalidDigits)
+	_, err = Generate(rfc4226Secret, 0, 9, SHA1)
+	assert.ErrorIs(t, err, ErrInvalidDigits)
+	_, err = Generate(rfc4226Secret, 0, 6, "MD5")
+	assert.ErrorIs(t, err, ErrInvalidAlgorithm)
+}
+
+func TestGenerateSecret(t *testing.T) {
+	secret, err := GenerateSecret(0)
+	assert.NoError(t, err)
+	assert.Len(t, secret, DefaultSecretSize)
+	other, err := GenerateSecret(0)
+	assert.NoError(t, err)
+	assert.NotEqual(t, secret, other)
+
+	secret, err = GenerateSecret(32)
+	assert.NoError(t, err)
+	assert.Len(t, secret, 32)
+}
+
+func TestHOTP(t *testing.T) {
+	h := &HOTP{Secret: rfc4226Secret, LookAhead: 2}
+	code, err := h.Generate(3)
+	assert.NoError(t, err)
+	assert.Equal(t, "969429", code)
+
+	next, err := h.Validate("359152", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), next)
+
+	// resynchronize within the look-ahead window
+	next, err = h.Validate("338314", next)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(5), next)
+
+	// used and out of window codes
+	next, err = h.Validate("338314", next)
+	assert.ErrorIs(t, err, ErrInvalidCode)
+	assert.Equal(t, uint64(5), next)
+	_, err = h.Validate("520489", 5)
+	assert.ErrorIs(t, err, ErrInvalidCode)
+	_, err = h.Validate("", 5)
+	assert.ErrorIs(t, err, ErrInvalidCode)
+
+	_, err = (&HOTP{Secret: rfc4226Secret, Digits: 10}).Validate("755224", 0)
+	assert.ErrorIs(t, err, ErrInvalidDigits)
+}
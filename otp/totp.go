@@ -0,0 +1,115 @@
+package otp
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultPeriod is the time step used when Period is zero.
+const DefaultPeriod = 30 * time.Second
+
+// TOTP generates and validates time based codes (RFC 6238).
+type TOTP struct {
+	Secret    []byte
+	Digits    int
+	Algorithm Algorithm
+	// Period is the time step, a whole number of seconds.
+	Period time.Duration
+	// Skew is how many time steps before and after the current one
+	// Validate accepts, to allow for clock drift and typing delay.
+	Skew uint
+	// Replay, if set, is called with the time step of a matching code
+	// before Validate accepts it. Returning an error, typically
+	// ErrReplayedCode when the step was already used for this secret,
+	// rejects the code.
+	Replay func(step uint64) error
+	// Now returns the current time, time.Now if nil.
+	Now func() time.Time
+}
+
+// Step returns the time step of at.
+func (t *TOTP) Step(at time.Time) (uint64, error) {
+	period := t.Period
+	if period == 0 {
+		period = DefaultPeriod
+	}
+	if period < time.Second || period%time.Second != 0 {
+		return 0, fmt.Errorf("otp: invalid period %s", period)
+	}
+	unix := at.Unix()
+	if unix < 0 {
+		return 0, fmt.Errorf("otp: time %s is before the unix epoch", at)
+	}
+	return uint64(unix) / uint64(period/time.Second), nil
+}
+
+// Generate returns the code at the time at.
+func (t *TOTP) Generate(at time.Time) (string, error) {
+	step, err := t.Step(at)
+	if err != nil {
+		return "", err
+	}
+	return Generate(t.Secret, step, t.Digits, t.Algorithm)
+}
+
+// Validate checks code at the current time.
+func (t *TOTP) Validate(code string) error {
+	now := time.Now
+	if t.Now != nil {
+		now = t.Now
+	}
+	return t.ValidateAt(code, now())
+}
+
+// ValidateAt checks code against the time step of at and Skew steps around
+// it, then passes the matching step to Replay.
+func (t *TOTP) ValidateAt(code string, at time.Time) error {
+	step, err := t.Step(at)
+	if err != nil {
+		return err
+	}
+	// the current step first, then the closest ones
+	for i := uint64(0); i <= uint64(t.Skew); i++ {
+		steps := []uint64{step + i}
+		if i > 0 && i <= step {
+			steps = append(steps, step-i)
+		}
+		for _, s := range steps {
+			ok, err := match(t.Secret, s, t.Digits, t.Algorithm, code)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				continue
+			}
+			if t.Replay != nil {
+				return t.Replay(s)
+			}
+			return nil
+		}
+	}
+	return ErrInvalidCode
+}
+
+// LastStep is a Replay hook for a single secret that only accepts time
+// steps after the last accepted one, so each code is used at most once and
+// older codes within the skew window are rejected after a newer one. It is
+// safe for concurrent use.
+type LastStep struct {
+	mu   sync.Mutex
+	step uint64
+	used bool
+}
+
+// Check records step, or return ErrReplayedCode if it is not after the
+// last recorded step.
+func (l *LastStep) Check(step uint64) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.used && step <= l.step {
+		return ErrReplayedCode
+	}
+	l.step, l.used = step, true
+	return nil
+}
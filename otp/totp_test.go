@@ -0,0 +1,146 @@
+package otp
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func ExampleTOTP() {
+	var used LastStep
+	totp := &TOTP{
+		Secret: []byte("12345678901234567890"),
+		Skew:   1,
+		Replay: used.Check,
+		Now:    func() time.Time { return time.Unix(1111111111, 0) },
+	}
+	code, err := totp.Generate(time.Unix(1111111109, 0))
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(code)
+	fmt.Println(totp.Validate(code))
+	fmt.Println(totp.Validate(code))
+	// Output:
+	// 081804
+	// <nil>
+	// otp: code already used
+}
+
+func TestTOTPGenerate(t *testing.T) {
+	// RFC 6238, appendix B
+	secrets := map[Algorithm][]byte{
+		SHA1:   []byte("12345678901234567890"),
+		SHA256: []byte("12345678901234567890123456789012"),
+		SHA512: []byte("1234567890123456789012345678901234567890123456789012345678901234"),
+	}
+	for _, tt := range []struct {
+		time  int64
+		codes map[Algorithm]string
+	}{
+		{59, map[Algorithm]string{SHA1: "94287082", SHA256: "46119246", SHA512: "90693936"}},
+		{1111111109, map[Algorithm]string{SHA1: "07081804", SHA256: "68084774", SHA512: "25091201"}},
+		{1111111111, map[Algorithm]string{SHA1: "14050471", SHA256: "67062674", SHA512: "99943326"}},
+		{1234567890, map[Algorithm]string{SHA1: "89005924", SHA256: "91819424", SHA512: "93441116"}},
+		{2000000000, map[Algorithm]string{SHA1: "69279037", SHA256: "90698825", SHA512: "38618901"}},
+		{20000000000, map[Algorithm]string{SHA1: "65353130", SHA256: "77737706", SHA512: "47863826"}},
+	} {
+		for alg, expected := range tt.codes {
+			totp := &TOTP{Secret: secrets[alg], Digits: 8, Algorithm: alg}
+			code, err := totp.Generate(time.Unix(tt.time, 0))
+			assert.NoError(t, err)
+			assert.Equal(t, expected, code, "%d %s", tt.time, alg)
+		}
+	}
+
+	totp := &TOTP{Secret: rfc4226Secret, Period: 60 * time.Second}
+	step, err := totp.Step(time.Unix(119, 0))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), step)
+
+	for _, period := range []time.Duration{time.Millisecond, 1500 * time.Millisecond, -time.Second} {
+		_, err = (&TOTP{Secret: rfc4226Secret, Period: period}).Generate(time.Now())
+		assert.Error(t, err)
+	}
+	_, err = totp.Generate(time.Unix(-1, 0))
+	assert.Error(t, err)
+}
+
+func TestTOTPValidate(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	totp := &TOTP{Secret: rfc4226Secret, Now: func() time.Time { return now }}
+	current, err := totp.Generate(now)
+	assert.NoError(t, err)
+	previous, err := totp.Generate(now.Add(-30 * time.Second))
+	assert.NoError(t, err)
+	next, err := totp.Generate(now.Add(30 * time.Second))
+	assert.NoError(t, err)
+	old, err := totp.Generate(now.Add(-60 * time.Second))
+	assert.NoError(t, err)
+
+	assert.NoError(t, totp.Validate(current))
+	assert.ErrorIs(t, totp.Validate(previous), ErrInvalidCode)
+
+	totp.Skew = 1
+	assert.NoError(t, totp.Validate(previous))
+	assert.NoError(t, totp.Validate(next))
+	assert.ErrorIs(t, totp.Validate(old), ErrInvalidCode)
+	assert.ErrorIs(t, totp.Validate("000000"), ErrInvalidCode)
+
+	// the replay hook receives the matching step
+	var steps []uint64
+	totp.Replay = func(step uint64) error {
+		steps = append(steps, step)
+		return nil
+	}
+	assert.NoError(t, totp.Validate(previous))
+	assert.NoError(t, totp.Validate(current))
+	assert.NoError(t, totp.Validate(next))
+	assert.Equal(t, []uint64{37037036, 37037037, 37037038}, steps)
+
+	// skew near the epoch
+	totp = &TOTP{Secret: rfc4226Secret, Skew: 2}
+	code, err := totp.Generate(time.Unix(0, 0))
+	assert.NoError(t, err)
+	assert.NoError(t, totp.ValidateAt(code, time.Unix(30, 0)))
+}
+
+func TestLastStep(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	var used LastStep
+	totp := &TOTP{Secret: rfc4226Secret, Skew: 1, Replay: used.Check, Now: func() time.Time { return now }}
+	current, err := totp.Generate(now)
+	assert.NoError(t, err)
+	previous, err := totp.Generate(now.Add(-30 * time.Second))
+	assert.NoError(t, err)
+
+	assert.NoError(t, totp.Validate(current))
+	assert.ErrorIs(t, totp.Validate(current), ErrReplayedCode)
+	// an older code is rejected once a newer one was used
+	assert.ErrorIs(t, totp.Validate(previous), ErrReplayedCode)
+
+	now = now.Add(30 * time.Second)
+	next, err := totp.Generate(now)
+	assert.NoError(t, err)
+	assert.NoError(t, totp.Validate(next))
+
+	// concurrent validations of the same code accept it once
+	var l LastStep
+	var wg sync.WaitGroup
+	accepted := make(chan struct{}, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if l.Check(1) == nil {
+				accepted <- struct{}{}
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Len(t, accepted, 1)
+}
@@ -0,0 +1,180 @@
+package otp
+
+import (
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	TypeTOTP = "totp"
+	TypeHOTP = "hotp"
+)
+
+var ErrInvalidURI = errors.New("otp: invalid otpauth uri")
+
+var secretEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// EncodeSecret encodes secret as unpadded base32, the form shown to users
+// for manual entry and used in otpauth URIs.
+func EncodeSecret(secret []byte) string {
+	return secretEncoding.EncodeToString(secret)
+}
+
+// DecodeSecret decodes a base32 secret, ignoring case, spaces, dashes and
+// padding.
+func DecodeSecret(s string) ([]byte, error) {
+	s = strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' || r == '=' {
+			return -1
+		}
+		return r
+	}, strings.ToUpper(s))
+	secret, err := secretEncoding.DecodeString(s)
+	if err != nil || len(secret) == 0 {
+		return nil, fmt.Errorf("%w: malformed base32", ErrInvalidSecret)
+	}
+	return secret, nil
+}
+
+// Key is the content of an otpauth:// URI
+// (https://github.com/google/google-authenticator/wiki/Key-Uri-Format).
+// Zero Algorithm, Digits and Period mean SHA1, DefaultDigits and
+// DefaultPeriod.
+type Key struct {
+	Type      string
+	Issuer    string
+	Account   string
+	Secret    []byte
+	Algorithm Algorithm
+	Digits    int
+	// Period is the time step of a TOTP key.
+	Period time.Duration
+	// Counter is the initial counter of a HOTP key.
+	Counter uint64
+}
+
+// URI encodes k as otpauth://TYPE/ISSUER:ACCOUNT?secret=...&issuer=...,
+// typically rendered as a QR code.
+func (k *Key) URI() (string, error) {
+	if k.Type != TypeTOTP && k.Type != TypeHOTP {
+		return "", fmt.Errorf("%w: type %q", ErrInvalidURI, k.Type)
+	}
+	if k.Account == "" {
+		return "", fmt.Errorf("%w: missing account name", ErrInvalidURI)
+	}
+	if strings.Contains(k.Issuer, ":") || strings.Contains(k.Account, ":") {
+		return "", fmt.Errorf("%w: issuer and account must not contain a colon", ErrInvalidURI)
+	}
+	if len(k.Secret) == 0 {
+		return "", ErrInvalidSecret
+	}
+
+	label := url.PathEscape(k.Account)
+	params := []string{"secret=" + EncodeSecret(k.Secret)}
+	if k.Issuer != "" {
+		label = url.PathEscape(k.Issuer) + ":" + label
+		// authenticator apps expect %20 rather than + for spaces
+		params = append(params, "issuer="+strings.ReplaceAll(url.QueryEscape(k.Issuer), "+", "%20"))
+	}
+	if k.Algorithm != "" {
+		if _, ok := hashes[k.Algorithm]; !ok {
+			return "", fmt.Errorf("%w: %q", ErrInvalidAlgorithm, k.Algorithm)
+		}
+		params = append(params, "algorithm="+string(k.Algorithm))
+	}
+	if k.Digits != 0 {
+		if k.Digits < 6 || k.Digits > 8 {
+			return "", ErrInvalidDigits
+		}
+		params = append(params, "digits="+strconv.Itoa(k.Digits))
+	}
+	if k.Type == TypeHOTP {
+		params = append(params, "counter="+strconv.FormatUint(k.Counter, 10))
+	} else if k.Period != 0 {
+		if k.Period < time.Second || k.Period%time.Second != 0 {
+			return "", fmt.Errorf("%w: invalid period %s", ErrInvalidURI, k.Period)
+		}
+		params = append(params, "period="+strconv.FormatInt(int64(k.Period/time.Second), 10))
+	}
+	return "otpauth://" + k.Type + "/" + label + "?" + strings.Join(params, "&"), nil
+}
+
+// ParseURI parses an otpauth:// URI.
+func ParseURI(uri string) (*Key, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidURI, err)
+	}
+	if u.Scheme != "otpauth" {
+		return nil, fmt.Errorf("%w: scheme %q", ErrInvalidURI, u.Scheme)
+	}
+	k := &Key{Type: strings.ToLower(u.Host)}
+	if k.Type != TypeTOTP && k.Type != TypeHOTP {
+		return nil, fmt.Errorf("%w: type %q", ErrInvalidURI, u.Host)
+	}
+
+	label := strings.TrimPrefix(u.Path, "/")
+	if issuer, account, ok := strings.Cut(label, ":"); ok {
+		k.Issuer, k.Account = issuer, strings.TrimLeft(account, " ")
+	} else {
+		k.Account = label
+	}
+	if k.Account == "" {
+		return nil, fmt.Errorf("%w: missing account name", ErrInvalidURI)
+	}
+
+	query := u.Query()
+	if issuer := query.Get("issuer"); issuer != "" {
+		if k.Issuer != "" && k.Issuer != issuer {
+			return nil, fmt.Errorf("%w: issuer %q does not match the label", ErrInvalidURI, issuer)
+		}
+		k.Issuer = issuer
+	}
+	if k.Secret, err = DecodeSecret(query.Get("secret")); err != nil {
+		return nil, err
+	}
+	if alg := query.Get("algorithm"); alg != "" {
+		k.Algorithm = Algorithm(strings.ToUpper(alg))
+		if _, ok := hashes[k.Algorithm]; !ok {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidAlgorithm, alg)
+		}
+	}
+	if digits := query.Get("digits"); digits != "" {
+		if k.Digits, err = strconv.Atoi(digits); err != nil || k.Digits < 6 || k.Digits > 8 {
+			return nil, ErrInvalidDigits
+		}
+	}
+	if period := query.Get("period"); period != "" && k.Type == TypeTOTP {
+		seconds, err := strconv.ParseUint(period, 10, 32)
+		if err != nil || seconds == 0 {
+			return nil, fmt.Errorf("%w: invalid period %q", ErrInvalidURI, period)
+		}
+		k.Period = time.Duration(seconds) * time.Second
+	}
+	if k.Type == TypeHOTP {
+		counter := query.Get("counter")
+		if counter == "" {
+			return nil, fmt.Errorf("%w: missing counter", ErrInvalidURI)
+		}
+		if k.Counter, err = strconv.ParseUint(counter, 10, 64); err != nil {
+			return nil, fmt.Errorf("%w: invalid counter %q", ErrInvalidURI, counter)
+		}
+	}
+	return k, nil
+}
+
+// TOTP returns a TOTP for the key, Skew, Replay and Now are left for the
+// caller to set.
+func (k *Key) TOTP() *TOTP {
+	return &TOTP{Secret: k.Secret, Digits: k.Digits, Algorithm: k.Algorithm, Period: k.Period}
+}
+
+// HOTP returns a HOTP for the key, Counter is the caller's to track.
+func (k *Key) HOTP() *HOTP {
+	return &HOTP{Secret: k.Secret, Digits: k.Digits, Algorithm: k.Algorithm}
+}
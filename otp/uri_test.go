@@ -0,0 +1,126 @@
+package otp
+
+import (
+	"fmt"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func ExampleKey_URI() {
+	key := &Key{
+		Type:    TypeTOTP,
+		Issuer:  "ACME Co",
+		Account: "john.doe@email.com",
+		Secret:  []byte("Hello!\xde\xad\xbe\xef"),
+	}
+	uri, err := key.URI()
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(uri)
+	// Output: otpauth://totp/ACME%20Co:john.doe@email.com?secret=JBSWY3DPEHPK3PXP&issuer=ACME%20Co
+}
+
+func ExampleParseURI() {
+	key, err := ParseURI("otpauth://totp/ACME%20Co:john.doe@email.com?secret=JBSWY3DPEHPK3PXP&issuer=ACME%20Co&algorithm=SHA256&digits=8&period=60")
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(key.Issuer, key.Account, key.Algorithm, key.Digits, key.Period)
+	// Output: ACME Co john.doe@email.com SHA256 8 1m0s
+}
+
+func TestSecret(t *testing.T) {
+	secret := []byte("Hello!\xde\xad\xbe\xef")
+	assert.Equal(t, "JBSWY3DPEHPK3PXP", EncodeSecret(secret))
+	for _, s := range []string{"JBSWY3DPEHPK3PXP", "jbsw y3dp ehpk 3pxp", "JBSW-Y3DP-EHPK-3PXP"} {
+		decoded, err := DecodeSecret(s)
+		assert.NoError(t, err)
+		assert.Equal(t, secret, decoded)
+	}
+	decoded, err := DecodeSecret("GEZDGNBV")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("12345"), decoded)
+	decoded, err = DecodeSecret("GEZDGNBVGY======")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("123456"), decoded)
+
+	for _, s := range []string{"", "JBSWY3DPEHPK3PX1", "JBS"} {
+		_, err = DecodeSecret(s)
+		assert.ErrorIs(t, err, ErrInvalidSecret, s)
+	}
+}
+
+func TestURI(t *testing.T) {
+	for _, key := range []*Key{
+		{Type: TypeTOTP, Account: "trumanwong", Secret: rfc4226Secret},
+		{Type: TypeTOTP, Issuer: "A&B Co", Account: "a b@example.com", Secret: rfc4226Secret, Algorithm: SHA512, Digits: 8, Period: time.Minute},
+		{Type: TypeHOTP, Issuer: "ACME", Account: "trumanwong", Secret: rfc4226Secret, Digits: 7, Counter: 42},
+	} {
+		uri, err := key.URI()
+		assert.NoError(t, err)
+		parsed, err := ParseURI(uri)
+		assert.NoError(t, err, uri)
+		assert.Equal(t, key, parsed, uri)
+	}
+
+	uri, err := (&Key{Type: TypeHOTP, Account: "trumanwong", Secret: rfc4226Secret}).URI()
+	assert.NoError(t, err)
+	assert.Equal(t, "otpauth://hotp/trumanwong?secret=GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ&counter=0", uri)
+
+	for _, key := range []*Key{
+		{Type: "motp", Account: "trumanwong", Secret: rfc4226Secret},
+		{Type: TypeTOTP, Secret: rfc4226Secret},
+		{Type: TypeTOTP, Issuer: "a:b", Account: "trumanwong", Secret: rfc4226Secret},
+		{Type: TypeTOTP, Account: "trumanwong", Secret: rfc4226Secret, Period: time.Millisecond},
+	} {
+		_, err = key.URI()
+		assert.ErrorIs(t, err, ErrInvalidURI)
+	}
+	_, err = (&Key{Type: TypeTOTP, Account: "trumanwong"}).URI()
+	assert.ErrorIs(t, err, ErrInvalidSecret)
+	_, err = (&Key{Type: TypeTOTP, Account: "trumanwong", Secret: rfc4226Secret, Digits: 4}).URI()
+	assert.ErrorIs(t, err, ErrInvalidDigits)
+	_, err = (&Key{Type: TypeTOTP, Account: "trumanwong", Secret: rfc4226Secret, Algorithm: "MD5"}).URI()
+	assert.ErrorIs(t, err, ErrInvalidAlgorithm)
+}
+
+func TestParseURI(t *testing.T) {
+	// issuer only in the label, lower case parameters
+	key, err := ParseURI("otpauth://TOTP/ACME:%20trumanwong?secret=gezdgnbv&algorithm=sha1")
+	assert.NoError(t, err)
+	assert.Equal(t, &Key{Type: TypeTOTP, Issuer: "ACME", Account: "trumanwong", Secret: []byte("12345"), Algorithm: SHA1}, key)
+
+	// the key drives the generators
+	key, err = ParseURI("otpauth://totp/trumanwong?secret=GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ&digits=8")
+	assert.NoError(t, err)
+	code, err := key.TOTP().Generate(time.Unix(59, 0))
+	assert.NoError(t, err)
+	assert.Equal(t, "94287082", code)
+	key, err = ParseURI("otpauth://hotp/trumanwong?secret=GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ&counter=1")
+	assert.NoError(t, err)
+	code, err = key.HOTP().Generate(key.Counter)
+	assert.NoError(t, err)
+	assert.Equal(t, "287082", code)
+
+	for uri, expected := range map[string]error{
+		"https://totp/trumanwong?secret=GEZDGNBV":                 ErrInvalidURI,
+		"otpauth://motp/trumanwong?secret=GEZDGNBV":               ErrInvalidURI,
+		"otpauth://totp/?secret=GEZDGNBV":                         ErrInvalidURI,
+		"otpauth://totp/A:trumanwong?secret=GEZDGNBV&issuer=B":    ErrInvalidURI,
+		"otpauth://totp/trumanwong?secret=GEZDGNBV&period=0":      ErrInvalidURI,
+		"otpauth://totp/trumanwong?secret=GEZDGNBV&period=x":      ErrInvalidURI,
+		"otpauth://hotp/trumanwong?secret=GEZDGNBV":               ErrInvalidURI,
+		"otpauth://hotp/trumanwong?secret=GEZDGNBV&counter=-1":    ErrInvalidURI,
+		"otpauth://totp/trumanwong":                               ErrInvalidSecret,
+		"otpauth://totp/trumanwong?secret=GEZDGNBV&digits=10":     ErrInvalidDigits,
+		"otpauth://totp/trumanwong?secret=GEZDGNBV&algorithm=MD5": ErrInvalidAlgorithm,
+		"%": ErrInvalidURI,
+	} {
+		_, err = ParseURI(uri)
+		assert.ErrorIs(t, err, expected, uri)
+	}
+}
@@ -0,0 +1,29 @@
+package cryptogo
+
+import (
+	"github.com/trumanwong/cryptogo/paseto"
+)
+
+// PasetoEncrypt encrypt message into a v4.local token with the 32 byte key,
+// footer is optional and stored in clear text.
+func PasetoEncrypt(key, message, footer []byte) (string, error) {
+	return paseto.V4Encrypt(key, message, footer, nil)
+}
+
+// PasetoDecrypt decrypt a v4.local token with key, return the message and
+// footer.
+func PasetoDecrypt(token string, key []byte) (message, footer []byte, err error) {
+	return paseto.V4Decrypt(token, key, nil)
+}
+
+// PasetoSign sign message into a v4.public token with an ed25519 private
+// key or its PKCS8 pem.
+func PasetoSign(privateKey interface{}, message, footer []byte) (string, error) {
+	return paseto.V4Sign(privateKey, message, footer, nil)
+}
+
+// PasetoVerify verify a v4.public token with an ed25519 public key or its
+// PKIX pem, return the message and footer.
+func PasetoVerify(token string, publicKey interface{}) (message, footer []byte, err error) {
+	return paseto.V4Verify(token, publicKey, nil)
+}
@@ -0,0 +1,99 @@
+// Package paseto implements PASETO (Platform-Agnostic Security Tokens)
+// versions 4 and 3: v4.local (XChaCha20 + BLAKE2b), v4.public (Ed25519),
+// v3.local (AES-256-CTR + HMAC-SHA384) and v3.public (ECDSA P-384).
+//
+// Unlike JOSE, the version and purpose of a token fix all its algorithms,
+// so a token cannot select how it is verified.
+package paseto
+
+import (
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrInvalidToken     = errors.New("paseto: invalid token")
+	ErrInvalidKey       = errors.New("paseto: invalid key")
+	ErrInvalidSignature = errors.New("paseto: invalid signature")
+	ErrDecryption       = errors.New("paseto: message authentication failed")
+)
+
+const (
+	headerV4Local  = "v4.local."
+	headerV4Public = "v4.public."
+	headerV3Local  = "v3.local."
+	headerV3Public = "v3.public."
+)
+
+// nonceSize is the random nonce of the local tokens of both versions.
+const nonceSize = 32
+
+var encoding = base64.RawURLEncoding
+
+// Footer returns the decoded footer of token without verifying it, for
+// example to read a key id. Nothing in it can be trusted before the token is
+// decrypted or verified.
+func Footer(token string) ([]byte, error) {
+	parts := strings.Split(token, ".")
+	switch len(parts) {
+	case 3:
+		return nil, nil
+	case 4:
+		footer, err := encoding.DecodeString(parts[3])
+		if err != nil {
+			return nil, fmt.Errorf("%w: malformed footer", ErrInvalidToken)
+		}
+		return footer, nil
+	}
+	return nil, fmt.Errorf("%w: token contains %d parts", ErrInvalidToken, len(parts))
+}
+
+// pae is the pre-authentication encoding of the PASETO specification.
+func pae(pieces ...[]byte) []byte {
+	out := binary.LittleEndian.AppendUint64(nil, uint64(len(pieces)))
+	for _, p := range pieces {
+		// the most significant bit is cleared for interoperability
+		out = binary.LittleEndian.AppendUint64(out, uint64(len(p))&^(1<<63))
+		out = append(out, p...)
+	}
+	return out
+}
+
+// encode builds header || base64url(body) [|| "." || base64url(footer)].
+func encode(header string, body, footer []byte) string {
+	token := header + encoding.EncodeToString(body)
+	if len(footer) > 0 {
+		token += "." + encoding.EncodeToString(footer)
+	}
+	return token
+}
+
+// decode checks the header of token and return its decoded body and
+// footer.
+func decode(token, header string) (body, footer []byte, err error) {
+	if !strings.HasPrefix(token, header) {
+		return nil, nil, fmt.Errorf("%w: not a %s token", ErrInvalidToken, strings.TrimSuffix(header, "."))
+	}
+	parts := strings.Split(token[len(header):], ".")
+	if len(parts) > 2 {
+		return nil, nil, fmt.Errorf("%w: too many parts", ErrInvalidToken)
+	}
+	if body, err = encoding.DecodeString(parts[0]); err != nil {
+		return nil, nil, fmt.Errorf("%w: malformed payload", ErrInvalidToken)
+	}
+	if len(parts) == 2 {
+		if footer, err = encoding.DecodeString(parts[1]); err != nil {
+			return nil, nil, fmt.Errorf("%w: malformed footer", ErrInvalidToken)
+		}
+	}
+	return body, footer, nil
+}
+
+// equal compares two MACs in constant time.
+func equal(a, b []byte) bool {
+	return subtle.ConstantTimeCompare(a, b) == 1
+}
@@ -0,0 +1,50 @@
+package paseto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPae(t *testing.T) {
+	assert.Equal(t, []byte{0, 0, 0, 0, 0, 0, 0, 0}, pae())
+	assert.Equal(t, []byte{1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}, pae([]byte{}))
+	assert.Equal(t, []byte{
+		2, 0, 0, 0, 0, 0, 0, 0,
+		4, 0, 0, 0, 0, 0, 0, 0, 't', 'e', 's', 't',
+		1, 0, 0, 0, 0, 0, 0, 0, '!',
+	}, pae([]byte("test"), []byte("!")))
+}
+
+func TestFooter(t *testing.T) {
+	footer, err := Footer("v4.local.AAAA.eyJraWQiOiJ0cnVtYW53b25nIn0")
+	assert.NoError(t, err)
+	assert.Equal(t, `{"kid":"trumanwong"}`, string(footer))
+
+	footer, err = Footer("v4.local.AAAA")
+	assert.NoError(t, err)
+	assert.Nil(t, footer)
+
+	_, err = Footer("v4.local")
+	assert.ErrorIs(t, err, ErrInvalidToken)
+	_, err = Footer("v4.local.AAAA.!!")
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestDecode(t *testing.T) {
+	body, footer, err := decode("v4.local.AAAA.AQ", headerV4Local)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0, 0, 0}, body)
+	assert.Equal(t, []byte{1}, footer)
+
+	for _, token := range []string{
+		"v4.public.AAAA",
+		"v3.local.AAAA",
+		"v4.local.AAAA.AQ.AQ",
+		"v4.local.AA==",
+		"v4.local.AAAA.A=",
+	} {
+		_, _, err = decode(token, headerV4Local)
+		assert.ErrorIs(t, err, ErrInvalidToken, token)
+	}
+}
@@ -0,0 +1,162 @@
+package paseto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha512"
+	"fmt"
+	"io"
+
+	"github.com/trumanwong/cryptogo/ecc"
+	"golang.org/x/crypto/hkdf"
+)
+
+// v3SignatureSize is the size of a P-384 r||s signature.
+const v3SignatureSize = 96
+
+// V3Encrypt encrypts message into a v3.local token with the 32 byte key.
+// footer and implicit are optional, as for V4Encrypt.
+func V3Encrypt(key, message, footer, implicit []byte) (string, error) {
+	n := make([]byte, nonceSize)
+	if _, err := rand.Read(n); err != nil {
+		return "", err
+	}
+	return v3Encrypt(key, n, message, footer, implicit)
+}
+
+// V3Decrypt decrypts a v3.local token with key and the same implicit
+// assertion it was encrypted with, return the message and footer.
+func V3Decrypt(token string, key, implicit []byte) (message, footer []byte, err error) {
+	if len(key) != 32 {
+		return nil, nil, fmt.Errorf("%w: v3.local requires a 32 byte key", ErrInvalidKey)
+	}
+	body, footer, err := decode(token, headerV3Local)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(body) < nonceSize+sha512.Size384 {
+		return nil, nil, fmt.Errorf("%w: payload is too short", ErrInvalidToken)
+	}
+	n, c, t := body[:nonceSize], body[nonceSize:len(body)-sha512.Size384], body[len(body)-sha512.Size384:]
+
+	ek, n2, ak, err := v3Keys(key, n)
+	if err != nil {
+		return nil, nil, err
+	}
+	mac := hmac.New(sha512.New384, ak)
+	mac.Write(pae([]byte(headerV3Local), n, c, footer, implicit))
+	if !equal(t, mac.Sum(nil)) {
+		return nil, nil, ErrDecryption
+	}
+	block, err := aes.NewCipher(ek)
+	if err != nil {
+		return nil, nil, err
+	}
+	message = make([]byte, len(c))
+	cipher.NewCTR(block, n2).XORKeyStream(message, c)
+	return message, footer, nil
+}
+
+func v3Encrypt(key, n, message, footer, implicit []byte) (string, error) {
+	if len(key) != 32 {
+		return "", fmt.Errorf("%w: v3.local requires a 32 byte key", ErrInvalidKey)
+	}
+	ek, n2, ak, err := v3Keys(key, n)
+	if err != nil {
+		return "", err
+	}
+	block, err := aes.NewCipher(ek)
+	if err != nil {
+		return "", err
+	}
+	c := make([]byte, len(message))
+	cipher.NewCTR(block, n2).XORKeyStream(c, message)
+
+	mac := hmac.New(sha512.New384, ak)
+	mac.Write(pae([]byte(headerV3Local), n, c, footer, implicit))
+	body := append(append(append([]byte(nil), n...), c...), mac.Sum(nil)...)
+	return encode(headerV3Local, body, footer), nil
+}
+
+// v3Keys derives the AES-256 key and CTR nonce and the HMAC key for the
+// nonce n with HKDF-SHA384.
+func v3Keys(key, n []byte) (ek, n2, ak []byte, err error) {
+	tmp := make([]byte, 48)
+	info := append([]byte("paseto-encryption-key"), n...)
+	if _, err = io.ReadFull(hkdf.New(sha512.New384, key, nil, info), tmp); err != nil {
+		return nil, nil, nil, err
+	}
+	ak = make([]byte, 48)
+	info = append([]byte("paseto-auth-key-for-aead"), n...)
+	if _, err = io.ReadFull(hkdf.New(sha512.New384, key, nil, info), ak); err != nil {
+		return nil, nil, nil, err
+	}
+	return tmp[:32], tmp[32:], ak, nil
+}
+
+// V3Sign signs message into a v3.public token with a deterministic RFC 6979
+// signature. The private key is an *ecc.PrivateKey, *ecdsa.PrivateKey or a
+// SEC1/PKCS8 pem on P-384.
+func V3Sign(privateKey interface{}, message, footer, implicit []byte) (string, error) {
+	var prv *ecc.PrivateKey
+	switch key := privateKey.(type) {
+	case *ecc.PrivateKey:
+		prv = key
+	case *ecdsa.PrivateKey:
+		prv = ecc.ImportECDSA(key)
+	case []byte:
+		var err error
+		if prv, err = ecc.ParsePrivateKey(key); err != nil {
+			return "", fmt.Errorf("%w: %s", ErrInvalidKey, err)
+		}
+	default:
+		return "", fmt.Errorf("%w: %T is not an ecdsa private key", ErrInvalidKey, privateKey)
+	}
+	if prv.Curve != elliptic.P384() {
+		return "", fmt.Errorf("%w: v3.public requires curve P-384", ErrInvalidKey)
+	}
+	m2 := pae(prv.PublicKey.Marshal(ecc.Compressed), []byte(headerV3Public), message, footer, implicit)
+	sig, err := ecc.SignWithOptions(prv, m2, sha512.New384, &ecc.SignOptions{Encoding: ecc.P1363, Deterministic: true})
+	if err != nil {
+		return "", err
+	}
+	return encode(headerV3Public, append(append([]byte(nil), message...), sig...), footer), nil
+}
+
+// V3Verify verifies a v3.public token, return its message and footer. The
+// public key is an *ecc.PublicKey, *ecdsa.PublicKey or a PKIX pem on P-384.
+func V3Verify(token string, publicKey interface{}, implicit []byte) (message, footer []byte, err error) {
+	var pub *ecc.PublicKey
+	switch key := publicKey.(type) {
+	case *ecc.PublicKey:
+		pub = key
+	case *ecdsa.PublicKey:
+		pub = ecc.ImportECDSAPublic(key)
+	case []byte:
+		if pub, err = ecc.ParsePublicKey(key); err != nil {
+			return nil, nil, fmt.Errorf("%w: %s", ErrInvalidKey, err)
+		}
+	default:
+		return nil, nil, fmt.Errorf("%w: %T is not an ecdsa public key", ErrInvalidKey, publicKey)
+	}
+	if pub.Curve != elliptic.P384() {
+		return nil, nil, fmt.Errorf("%w: v3.public requires curve P-384", ErrInvalidKey)
+	}
+	body, footer, err := decode(token, headerV3Public)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(body) < v3SignatureSize {
+		return nil, nil, fmt.Errorf("%w: payload is too short", ErrInvalidToken)
+	}
+	message, sig := body[:len(body)-v3SignatureSize], body[len(body)-v3SignatureSize:]
+	m2 := pae(pub.Marshal(ecc.Compressed), []byte(headerV3Public), message, footer, implicit)
+	if !ecc.VerifyWithOptions(pub, m2, sig, sha512.New384, &ecc.SignOptions{Encoding: ecc.P1363}) {
+		return nil, nil, ErrInvalidSignature
+	}
+	return message, footer, nil
+}
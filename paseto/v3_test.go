@@ -0,0 +1,204 @@
+package paseto
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trumanwong/cryptogo/ecc"
+)
+
+var (
+	v3TestMessage  = []byte(`{"data":"this is a secret message","exp":"2022-01-01T00:00:00+00:00"}`)
+	v3TestFooter   = []byte(`{"kid":"trumanwong"}`)
+	v3TestImplicit = []byte("implicit")
+	v3TestPublic   = []byte(`-----BEGIN PUBLIC KEY-----
+MHYwEAYHKoZIzj0CAQYFK4EEACIDYgAEs8dCRjNkfei/QfkEXHH+Z9/c6kgSKkZw
+yohLLd2hGXysCtXUDrw+OrZ26vtdk5TxwHKxQFaQqBaT58fKlVFkWTukrs93CmOI
+CxqJKp1schMqSrNoJNh0VywP+ZepJvPN
+-----END PUBLIC KEY-----`)
+)
+
+func ExampleV3Encrypt() {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		log.Fatal(err)
+	}
+	token, err := V3Encrypt(key, []byte(`{"sub":"trumanwong"}`), nil, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	message, _, err := V3Decrypt(token, key, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(string(message))
+	// Output: {"sub":"trumanwong"}
+}
+
+// the v3.local vectors of https://github.com/paseto-standard/test-vectors
+var v3Vectors = []struct {
+	name, nonce, token, payload, footer, implicit string
+}{
+	{"3-E-1", "0000000000000000000000000000000000000000000000000000000000000000", "v3.local.AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAADbfcIURX_0pVZVU1mAESUzrKZAsRm2EsD6yBoZYn6cpVZNzSJOhSDN-sRaWjfLU-yn9OJH1J_B8GKtOQ9gSQlb8yk9Iza7teRdkiR89ZFyvPPsVjjFiepFUVcMa-LP18zV77f_crJrVXWa5PDNRkCSeHfBBeg", "{\"data\":\"this is a secret message\",\"exp\":\"2022-01-01T00:00:00+00:00\"}", "", ""},
+	{"3-E-2", "0000000000000000000000000000000000000000000000000000000000000000", "v3.local.AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAADbfcIURX_0pVZVU1mAESUzrKZAqhWxBMDgyBoZYn6cpVZNzSJOhSDN-sRaWjfLU-yn9OJH1J_B8GKtOQ9gSQlb8yk9IzZfaZpReVpHlDSwfuygx1riVXYVs-UjcrG_apl9oz3jCVmmJbRuKn5ZfD8mHz2db0A", "{\"data\":\"this is a hidden message\",\"exp\":\"2022-01-01T00:00:00+00:00\"}", "", ""},
+	{"3-E-3", "26f7553354482a1d91d4784627854b8da6b8042a7966523c2b404e8dbbe7f7f2", "v3.local.JvdVM1RIKh2R1HhGJ4VLjaa4BCp5ZlI8K0BOjbvn9_LwY78vQnDait-Q-sjhF88dG2B0ROIIykcrGHn8wzPbTrqObHhyoKpjy3cwZQzLdiwRsdEK5SDvl02_HjWKJW2oqGMOQJlxnt5xyhQjFJomwnt7WW_7r2VT0G704ifult011-TgLCyQ2X8imQhniG_hAQ4BydM", "{\"data\":\"this is a secret message\",\"exp\":\"2022-01-01T00:00:00+00:00\"}", "", ""},
+	{"3-E-4", "26f7553354482a1d91d4784627854b8da6b8042a7966523c2b404e8dbbe7f7f2", "v3.local.JvdVM1RIKh2R1HhGJ4VLjaa4BCp5ZlI8K0BOjbvn9_LwY78vQnDait-Q-sjhF88dG2B0X-4P3EcxGHn8wzPbTrqObHhyoKpjy3cwZQzLdiwRsdEK5SDvl02_HjWKJW2oqGMOQJlBZa_gOpVj4gv0M9lV6Pwjp8JS_MmaZaTA1LLTULXybOBZ2S4xMbYqYmDRhh3IgEk", "{\"data\":\"this is a hidden message\",\"exp\":\"2022-01-01T00:00:00+00:00\"}", "", ""},
+	{"3-E-5", "26f7553354482a1d91d4784627854b8da6b8042a7966523c2b404e8dbbe7f7f2", "v3.local.JvdVM1RIKh2R1HhGJ4VLjaa4BCp5ZlI8K0BOjbvn9_LwY78vQnDait-Q-sjhF88dG2B0ROIIykcrGHn8wzPbTrqObHhyoKpjy3cwZQzLdiwRsdEK5SDvl02_HjWKJW2oqGMOQJlkYSIbXOgVuIQL65UMdW9WcjOpmqvjqD40NNzed-XPqn1T3w-bJvitYpUJL_rmihc.eyJraWQiOiJVYmtLOFk2aXY0R1poRnA2VHgzSVdMV0xmTlhTRXZKY2RUM3pkUjY1WVp4byJ9", "{\"data\":\"this is a secret message\",\"exp\":\"2022-01-01T00:00:00+00:00\"}", "{\"kid\":\"UbkK8Y6iv4GZhFp6Tx3IWLWLfNXSEvJcdT3zdR65YZxo\"}", ""},
+	{"3-E-6", "26f7553354482a1d91d4784627854b8da6b8042a7966523c2b404e8dbbe7f7f2", "v3.local.JvdVM1RIKh2R1HhGJ4VLjaa4BCp5ZlI8K0BOjbvn9_LwY78vQnDait-Q-sjhF88dG2B0X-4P3EcxGHn8wzPbTrqObHhyoKpjy3cwZQzLdiwRsdEK5SDvl02_HjWKJW2oqGMOQJmSeEMphEWHiwtDKJftg41O1F8Hat-8kQ82ZIAMFqkx9q5VkWlxZke9ZzMBbb3Znfo.eyJraWQiOiJVYmtLOFk2aXY0R1poRnA2VHgzSVdMV0xmTlhTRXZKY2RUM3pkUjY1WVp4byJ9", "{\"data\":\"this is a hidden message\",\"exp\":\"2022-01-01T00:00:00+00:00\"}", "{\"kid\":\"UbkK8Y6iv4GZhFp6Tx3IWLWLfNXSEvJcdT3zdR65YZxo\"}", ""},
+	{"3-E-7", "26f7553354482a1d91d4784627854b8da6b8042a7966523c2b404e8dbbe7f7f2", "v3.local.JvdVM1RIKh2R1HhGJ4VLjaa4BCp5ZlI8K0BOjbvn9_LwY78vQnDait-Q-sjhF88dG2B0ROIIykcrGHn8wzPbTrqObHhyoKpjy3cwZQzLdiwRsdEK5SDvl02_HjWKJW2oqGMOQJkzWACWAIoVa0bz7EWSBoTEnS8MvGBYHHo6t6mJunPrFR9JKXFCc0obwz5N-pxFLOc.eyJraWQiOiJVYmtLOFk2aXY0R1poRnA2VHgzSVdMV0xmTlhTRXZKY2RUM3pkUjY1WVp4byJ9", "{\"data\":\"this is a secret message\",\"exp\":\"2022-01-01T00:00:00+00:00\"}", "{\"kid\":\"UbkK8Y6iv4GZhFp6Tx3IWLWLfNXSEvJcdT3zdR65YZxo\"}", "{\"test-vector\":\"3-E-7\"}"},
+	{"3-E-8", "26f7553354482a1d91d4784627854b8da6b8042a7966523c2b404e8dbbe7f7f2", "v3.local.JvdVM1RIKh2R1HhGJ4VLjaa4BCp5ZlI8K0BOjbvn9_LwY78vQnDait-Q-sjhF88dG2B0X-4P3EcxGHn8wzPbTrqObHhyoKpjy3cwZQzLdiwRsdEK5SDvl02_HjWKJW2oqGMOQJmZHSSKYR6AnPYJV6gpHtx6dLakIG_AOPhu8vKexNyrv5_1qoom6_NaPGecoiz6fR8.eyJraWQiOiJVYmtLOFk2aXY0R1poRnA2VHgzSVdMV0xmTlhTRXZKY2RUM3pkUjY1WVp4byJ9", "{\"data\":\"this is a hidden message\",\"exp\":\"2022-01-01T00:00:00+00:00\"}", "{\"kid\":\"UbkK8Y6iv4GZhFp6Tx3IWLWLfNXSEvJcdT3zdR65YZxo\"}", "{\"test-vector\":\"3-E-8\"}"},
+	{"3-E-9", "26f7553354482a1d91d4784627854b8da6b8042a7966523c2b404e8dbbe7f7f2", "v3.local.JvdVM1RIKh2R1HhGJ4VLjaa4BCp5ZlI8K0BOjbvn9_LwY78vQnDait-Q-sjhF88dG2B0X-4P3EcxGHn8wzPbTrqObHhyoKpjy3cwZQzLdiwRsdEK5SDvl02_HjWKJW2oqGMOQJlk1nli0_wijTH_vCuRwckEDc82QWK8-lG2fT9wQF271sgbVRVPjm0LwMQZkvvamqU.YXJiaXRyYXJ5LXN0cmluZy10aGF0LWlzbid0LWpzb24", "{\"data\":\"this is a hidden message\",\"exp\":\"2022-01-01T00:00:00+00:00\"}", "arbitrary-string-that-isn't-json", "{\"test-vector\":\"3-E-9\"}"},
+}
+
+func TestV3Vectors(t *testing.T) {
+	for _, tt := range v3Vectors {
+		t.Run(tt.name, func(t *testing.T) {
+			nonce, _ := hex.DecodeString(tt.nonce)
+			token, err := v3Encrypt(v4TestKey, nonce, []byte(tt.payload), []byte(tt.footer), []byte(tt.implicit))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.token, token)
+
+			message, footer, err := V3Decrypt(tt.token, v4TestKey, []byte(tt.implicit))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.payload, string(message))
+			assert.Equal(t, tt.footer, string(footer))
+		})
+	}
+}
+
+func TestV3Encrypt(t *testing.T) {
+	token, err := V3Encrypt(v4TestKey, v3TestMessage, v3TestFooter, v3TestImplicit)
+	assert.NoError(t, err)
+	message, footer, err := V3Decrypt(token, v4TestKey, v3TestImplicit)
+	assert.NoError(t, err)
+	assert.Equal(t, v3TestMessage, message)
+	assert.Equal(t, v3TestFooter, footer)
+
+	_, err = V3Encrypt(v4TestKey[:24], v3TestMessage, nil, nil)
+	assert.ErrorIs(t, err, ErrInvalidKey)
+}
+
+func TestV3Decrypt(t *testing.T) {
+	token, err := V3Encrypt(v4TestKey, []byte("trumanwong"), []byte("footer"), []byte("implicit"))
+	assert.NoError(t, err)
+
+	_, _, err = V3Decrypt(token, v4TestKey, nil)
+	assert.ErrorIs(t, err, ErrDecryption)
+	_, _, err = V3Decrypt(token, bytes.Repeat([]byte{1}, 32), []byte("implicit"))
+	assert.ErrorIs(t, err, ErrDecryption)
+
+	body, _, err := decode(token, headerV3Local)
+	assert.NoError(t, err)
+	body[len(body)-1] ^= 1
+	_, _, err = V3Decrypt(encode(headerV3Local, body, []byte("footer")), v4TestKey, []byte("implicit"))
+	assert.ErrorIs(t, err, ErrDecryption)
+
+	// a v4 token under the same key is rejected
+	v4, err := V4Encrypt(v4TestKey, []byte("trumanwong"), nil, nil)
+	assert.NoError(t, err)
+	_, _, err = V3Decrypt(v4, v4TestKey, nil)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+	_, _, err = V3Decrypt("v3.local.AAAA", v4TestKey, nil)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+	_, _, err = V3Decrypt(token, nil, []byte("implicit"))
+	assert.ErrorIs(t, err, ErrInvalidKey)
+}
+
+func ExampleV3Sign() {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		log.Fatal(err)
+	}
+	token, err := V3Sign(privateKey, []byte(`{"sub":"trumanwong"}`), []byte(`{"kid":"1"}`), nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	message, footer, err := V3Verify(token, &privateKey.PublicKey, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(string(message), string(footer))
+	// Output: {"sub":"trumanwong"} {"kid":"1"}
+}
+
+func TestV3Sign(t *testing.T) {
+	privateKey, err := ecc.GenerateKey(rand.Reader, elliptic.P384(), nil)
+	assert.NoError(t, err)
+
+	token, err := V3Sign(privateKey, v3TestMessage, v3TestFooter, v3TestImplicit)
+	assert.NoError(t, err)
+	// signatures are deterministic
+	again, err := V3Sign(privateKey, v3TestMessage, v3TestFooter, v3TestImplicit)
+	assert.NoError(t, err)
+	assert.Equal(t, token, again)
+
+	message, footer, err := V3Verify(token, &privateKey.PublicKey, v3TestImplicit)
+	assert.NoError(t, err)
+	assert.Equal(t, v3TestMessage, message)
+	assert.Equal(t, v3TestFooter, footer)
+
+	// pem keys
+	prvPem, err := privateKey.MarshalPEM(ecc.PKCS8)
+	assert.NoError(t, err)
+	pubPem, err := privateKey.PublicKey.MarshalPEM()
+	assert.NoError(t, err)
+	token, err = V3Sign(prvPem, v3TestMessage, nil, nil)
+	assert.NoError(t, err)
+	_, _, err = V3Verify(token, pubPem, nil)
+	assert.NoError(t, err)
+
+	p256, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	_, err = V3Sign(p256, v3TestMessage, nil, nil)
+	assert.ErrorIs(t, err, ErrInvalidKey)
+	_, err = V3Sign(v4TestKey, v3TestMessage, nil, nil)
+	assert.ErrorIs(t, err, ErrInvalidKey)
+	_, err = V3Sign("key", v3TestMessage, nil, nil)
+	assert.ErrorIs(t, err, ErrInvalidKey)
+}
+
+func TestV3Verify(t *testing.T) {
+	// signed with node's crypto module
+	token := "v3.public.eyJkYXRhIjoidGhpcyBpcyBhIHNlY3JldCBtZXNzYWdlIiwiZXhwIjoiMjAyMi0wMS0wMVQwMDowMDowMCswMDowMCJ9VS6e0YqStb1t8NxTTJLWgpTy9SUz_Fjd_EfRiUzrD7DOyqnHWtNDjlOrKzHcTIohnFSKEnZ-aO3sFktApHkxr1afQfNWS1RVR91CtRExjAIJ1lmZla7M8TgEI_TU2aXO.eyJraWQiOiJ0cnVtYW53b25nIn0"
+	message, footer, err := V3Verify(token, v3TestPublic, v3TestImplicit)
+	assert.NoError(t, err)
+	assert.Equal(t, v3TestMessage, message)
+	assert.Equal(t, v3TestFooter, footer)
+
+	_, _, err = V3Verify(token, v3TestPublic, nil)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	body, _, err := decode(token, headerV3Public)
+	assert.NoError(t, err)
+	_, _, err = V3Verify(encode(headerV3Public, body, nil), v3TestPublic, v3TestImplicit)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+	body[0] ^= 1
+	_, _, err = V3Verify(encode(headerV3Public, body, v3TestFooter), v3TestPublic, v3TestImplicit)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	otherKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	assert.NoError(t, err)
+	_, _, err = V3Verify(token, &otherKey.PublicKey, v3TestImplicit)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	_, _, err = V3Verify("v3.public.AAAA", v3TestPublic, nil)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+	_, _, err = V3Verify("v4"+token[2:], v3TestPublic, v3TestImplicit)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	p256, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	_, _, err = V3Verify(token, &p256.PublicKey, v3TestImplicit)
+	assert.ErrorIs(t, err, ErrInvalidKey)
+	_, _, err = V3Verify(token, []byte("key"), v3TestImplicit)
+	assert.ErrorIs(t, err, ErrInvalidKey)
+}
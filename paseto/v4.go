@@ -0,0 +1,137 @@
+package paseto
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"fmt"
+
+	cryptoed25519 "github.com/trumanwong/cryptogo/ed25519"
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/chacha20"
+)
+
+// V4Encrypt encrypts message into a v4.local token with the 32 byte key.
+// footer is appended in clear text and authenticated, implicit is
+// authenticated without being part of the token, both are optional.
+func V4Encrypt(key, message, footer, implicit []byte) (string, error) {
+	n := make([]byte, nonceSize)
+	if _, err := rand.Read(n); err != nil {
+		return "", err
+	}
+	return v4Encrypt(key, n, message, footer, implicit)
+}
+
+// V4Decrypt decrypts a v4.local token with key and the same implicit
+// assertion it was encrypted with, return the message and footer.
+func V4Decrypt(token string, key, implicit []byte) (message, footer []byte, err error) {
+	if len(key) != 32 {
+		return nil, nil, fmt.Errorf("%w: v4.local requires a 32 byte key", ErrInvalidKey)
+	}
+	body, footer, err := decode(token, headerV4Local)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(body) < nonceSize+32 {
+		return nil, nil, fmt.Errorf("%w: payload is too short", ErrInvalidToken)
+	}
+	n, c, t := body[:nonceSize], body[nonceSize:len(body)-32], body[len(body)-32:]
+
+	ek, n2, ak := v4Keys(key, n)
+	mac, _ := blake2b.New256(ak)
+	mac.Write(pae([]byte(headerV4Local), n, c, footer, implicit))
+	if !equal(t, mac.Sum(nil)) {
+		return nil, nil, ErrDecryption
+	}
+	message = make([]byte, len(c))
+	stream, err := chacha20.NewUnauthenticatedCipher(ek, n2)
+	if err != nil {
+		return nil, nil, err
+	}
+	stream.XORKeyStream(message, c)
+	return message, footer, nil
+}
+
+func v4Encrypt(key, n, message, footer, implicit []byte) (string, error) {
+	if len(key) != 32 {
+		return "", fmt.Errorf("%w: v4.local requires a 32 byte key", ErrInvalidKey)
+	}
+	ek, n2, ak := v4Keys(key, n)
+	c := make([]byte, len(message))
+	stream, err := chacha20.NewUnauthenticatedCipher(ek, n2)
+	if err != nil {
+		return "", err
+	}
+	stream.XORKeyStream(c, message)
+
+	mac, _ := blake2b.New256(ak)
+	mac.Write(pae([]byte(headerV4Local), n, c, footer, implicit))
+	body := append(append(append([]byte(nil), n...), c...), mac.Sum(nil)...)
+	return encode(headerV4Local, body, footer), nil
+}
+
+// v4Keys splits the key into the XChaCha20 key and nonce and the BLAKE2b
+// authentication key for the nonce n.
+func v4Keys(key, n []byte) (ek, n2, ak []byte) {
+	h, _ := blake2b.New(56, key)
+	h.Write([]byte("paseto-encryption-key"))
+	h.Write(n)
+	tmp := h.Sum(nil)
+
+	h, _ = blake2b.New256(key)
+	h.Write([]byte("paseto-auth-key-for-aead"))
+	h.Write(n)
+	return tmp[:32], tmp[32:], h.Sum(nil)
+}
+
+// V4Sign signs message into a v4.public token. The private key is an
+// ed25519.PrivateKey or a PKCS8 pem.
+func V4Sign(privateKey interface{}, message, footer, implicit []byte) (string, error) {
+	var prv ed25519.PrivateKey
+	switch key := privateKey.(type) {
+	case ed25519.PrivateKey:
+		prv = key
+	case []byte:
+		var err error
+		if prv, err = cryptoed25519.ParsePrivateKey(key); err != nil {
+			return "", fmt.Errorf("%w: %s", ErrInvalidKey, err)
+		}
+	default:
+		return "", fmt.Errorf("%w: %T is not an ed25519 private key", ErrInvalidKey, privateKey)
+	}
+	if len(prv) != ed25519.PrivateKeySize {
+		return "", ErrInvalidKey
+	}
+	sig := ed25519.Sign(prv, pae([]byte(headerV4Public), message, footer, implicit))
+	return encode(headerV4Public, append(append([]byte(nil), message...), sig...), footer), nil
+}
+
+// V4Verify verifies a v4.public token, return its message and footer. The
+// public key is an ed25519.PublicKey or a PKIX/X.509 certificate pem.
+func V4Verify(token string, publicKey interface{}, implicit []byte) (message, footer []byte, err error) {
+	var pub ed25519.PublicKey
+	switch key := publicKey.(type) {
+	case ed25519.PublicKey:
+		pub = key
+	case []byte:
+		if pub, err = cryptoed25519.ParsePublicKey(key); err != nil {
+			return nil, nil, fmt.Errorf("%w: %s", ErrInvalidKey, err)
+		}
+	default:
+		return nil, nil, fmt.Errorf("%w: %T is not an ed25519 public key", ErrInvalidKey, publicKey)
+	}
+	if len(pub) != ed25519.PublicKeySize {
+		return nil, nil, ErrInvalidKey
+	}
+	body, footer, err := decode(token, headerV4Public)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(body) < ed25519.SignatureSize {
+		return nil, nil, fmt.Errorf("%w: payload is too short", ErrInvalidToken)
+	}
+	message, sig := body[:len(body)-ed25519.SignatureSize], body[len(body)-ed25519.SignatureSize:]
+	if !ed25519.Verify(pub, pae([]byte(headerV4Public), message, footer, implicit), sig) {
+		return nil, nil, ErrInvalidSignature
+	}
+	return message, footer, nil
+}
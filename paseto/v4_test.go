@@ -0,0 +1,210 @@
+package paseto
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	v4TestKey, _  = hex.DecodeString("707172737475767778797a7b7c7d7e7f808182838485868788898a8b8c8d8e8f")
+	v4TestSeed, _ = hex.DecodeString("b4cbfb43df4ce210727d953e4a713307fa19bb7d9f85041438d9e11b942a3774")
+)
+
+func ExampleV4Encrypt() {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		log.Fatal(err)
+	}
+	token, err := V4Encrypt(key, []byte(`{"sub":"trumanwong"}`), []byte(`{"kid":"1"}`), nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	message, footer, err := V4Decrypt(token, key, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(string(message), string(footer))
+	// Output: {"sub":"trumanwong"} {"kid":"1"}
+}
+
+// the v4.local vectors of https://github.com/paseto-standard/test-vectors
+var v4Vectors = []struct {
+	name, nonce, token, payload, footer, implicit string
+}{
+	{"4-E-1", "0000000000000000000000000000000000000000000000000000000000000000", "v4.local.AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAQAr68PS4AXe7If_ZgesdkUMvSwscFlAl1pk5HC0e8kApeaqMfGo_7OpBnwJOAbY9V7WU6abu74MmcUE8YWAiaArVI8XJ5hOb_4v9RmDkneN0S92dx0OW4pgy7omxgf3S8c3LlQg", "{\"data\":\"this is a secret message\",\"exp\":\"2022-01-01T00:00:00+00:00\"}", "", ""},
+	{"4-E-2", "0000000000000000000000000000000000000000000000000000000000000000", "v4.local.AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAQAr68PS4AXe7If_ZgesdkUMvS2csCgglvpk5HC0e8kApeaqMfGo_7OpBnwJOAbY9V7WU6abu74MmcUE8YWAiaArVI8XIemu9chy3WVKvRBfg6t8wwYHK0ArLxxfZP73W_vfwt5A", "{\"data\":\"this is a hidden message\",\"exp\":\"2022-01-01T00:00:00+00:00\"}", "", ""},
+	{"4-E-3", "df654812bac492663825520ba2f6e67cf5ca5bdc13d4e7507a98cc4c2fcc3ad8", "v4.local.32VIErrEkmY4JVILovbmfPXKW9wT1OdQepjMTC_MOtjA4kiqw7_tcaOM5GNEcnTxl60WkwMsYXw6FSNb_UdJPXjpzm0KW9ojM5f4O2mRvE2IcweP-PRdoHjd5-RHCiExR1IK6t6-tyebyWG6Ov7kKvBdkrrAJ837lKP3iDag2hzUPHuMKA", "{\"data\":\"this is a secret message\",\"exp\":\"2022-01-01T00:00:00+00:00\"}", "", ""},
+	{"4-E-4", "df654812bac492663825520ba2f6e67cf5ca5bdc13d4e7507a98cc4c2fcc3ad8", "v4.local.32VIErrEkmY4JVILovbmfPXKW9wT1OdQepjMTC_MOtjA4kiqw7_tcaOM5GNEcnTxl60WiA8rd3wgFSNb_UdJPXjpzm0KW9ojM5f4O2mRvE2IcweP-PRdoHjd5-RHCiExR1IK6t4gt6TiLm55vIH8c_lGxxZpE3AWlH4WTR0v45nsWoU3gQ", "{\"data\":\"this is a hidden message\",\"exp\":\"2022-01-01T00:00:00+00:00\"}", "", ""},
+	{"4-E-5", "df654812bac492663825520ba2f6e67cf5ca5bdc13d4e7507a98cc4c2fcc3ad8", "v4.local.32VIErrEkmY4JVILovbmfPXKW9wT1OdQepjMTC_MOtjA4kiqw7_tcaOM5GNEcnTxl60WkwMsYXw6FSNb_UdJPXjpzm0KW9ojM5f4O2mRvE2IcweP-PRdoHjd5-RHCiExR1IK6t4x-RMNXtQNbz7FvFZ_G-lFpk5RG3EOrwDL6CgDqcerSQ.eyJraWQiOiJ6VmhNaVBCUDlmUmYyc25FY1Q3Z0ZUaW9lQTlDT2NOeTlEZmdMMVc2MGhhTiJ9", "{\"data\":\"this is a secret message\",\"exp\":\"2022-01-01T00:00:00+00:00\"}", "{\"kid\":\"zVhMiPBP9fRf2snEcT7gFTioeA9COcNy9DfgL1W60haN\"}", ""},
+	{"4-E-6", "df654812bac492663825520ba2f6e67cf5ca5bdc13d4e7507a98cc4c2fcc3ad8", "v4.local.32VIErrEkmY4JVILovbmfPXKW9wT1OdQepjMTC_MOtjA4kiqw7_tcaOM5GNEcnTxl60WiA8rd3wgFSNb_UdJPXjpzm0KW9ojM5f4O2mRvE2IcweP-PRdoHjd5-RHCiExR1IK6t6pWSA5HX2wjb3P-xLQg5K5feUCX4P2fpVK3ZLWFbMSxQ.eyJraWQiOiJ6VmhNaVBCUDlmUmYyc25FY1Q3Z0ZUaW9lQTlDT2NOeTlEZmdMMVc2MGhhTiJ9", "{\"data\":\"this is a hidden message\",\"exp\":\"2022-01-01T00:00:00+00:00\"}", "{\"kid\":\"zVhMiPBP9fRf2snEcT7gFTioeA9COcNy9DfgL1W60haN\"}", ""},
+	{"4-E-7", "df654812bac492663825520ba2f6e67cf5ca5bdc13d4e7507a98cc4c2fcc3ad8", "v4.local.32VIErrEkmY4JVILovbmfPXKW9wT1OdQepjMTC_MOtjA4kiqw7_tcaOM5GNEcnTxl60WkwMsYXw6FSNb_UdJPXjpzm0KW9ojM5f4O2mRvE2IcweP-PRdoHjd5-RHCiExR1IK6t40KCCWLA7GYL9KFHzKlwY9_RnIfRrMQpueydLEAZGGcA.eyJraWQiOiJ6VmhNaVBCUDlmUmYyc25FY1Q3Z0ZUaW9lQTlDT2NOeTlEZmdMMVc2MGhhTiJ9", "{\"data\":\"this is a secret message\",\"exp\":\"2022-01-01T00:00:00+00:00\"}", "{\"kid\":\"zVhMiPBP9fRf2snEcT7gFTioeA9COcNy9DfgL1W60haN\"}", "{\"test-vector\":\"4-E-7\"}"},
+	{"4-E-8", "df654812bac492663825520ba2f6e67cf5ca5bdc13d4e7507a98cc4c2fcc3ad8", "v4.local.32VIErrEkmY4JVILovbmfPXKW9wT1OdQepjMTC_MOtjA4kiqw7_tcaOM5GNEcnTxl60WiA8rd3wgFSNb_UdJPXjpzm0KW9ojM5f4O2mRvE2IcweP-PRdoHjd5-RHCiExR1IK6t5uvqQbMGlLLNYBc7A6_x7oqnpUK5WLvj24eE4DVPDZjw.eyJraWQiOiJ6VmhNaVBCUDlmUmYyc25FY1Q3Z0ZUaW9lQTlDT2NOeTlEZmdMMVc2MGhhTiJ9", "{\"data\":\"this is a hidden message\",\"exp\":\"2022-01-01T00:00:00+00:00\"}", "{\"kid\":\"zVhMiPBP9fRf2snEcT7gFTioeA9COcNy9DfgL1W60haN\"}", "{\"test-vector\":\"4-E-8\"}"},
+	{"4-E-9", "df654812bac492663825520ba2f6e67cf5ca5bdc13d4e7507a98cc4c2fcc3ad8", "v4.local.32VIErrEkmY4JVILovbmfPXKW9wT1OdQepjMTC_MOtjA4kiqw7_tcaOM5GNEcnTxl60WiA8rd3wgFSNb_UdJPXjpzm0KW9ojM5f4O2mRvE2IcweP-PRdoHjd5-RHCiExR1IK6t6tybdlmnMwcDMw0YxA_gFSE_IUWl78aMtOepFYSWYfQA.YXJiaXRyYXJ5LXN0cmluZy10aGF0LWlzbid0LWpzb24", "{\"data\":\"this is a hidden message\",\"exp\":\"2022-01-01T00:00:00+00:00\"}", "arbitrary-string-that-isn't-json", "{\"test-vector\":\"4-E-9\"}"},
+}
+
+func TestV4Vectors(t *testing.T) {
+	for _, tt := range v4Vectors {
+		t.Run(tt.name, func(t *testing.T) {
+			nonce, _ := hex.DecodeString(tt.nonce)
+			token, err := v4Encrypt(v4TestKey, nonce, []byte(tt.payload), []byte(tt.footer), []byte(tt.implicit))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.token, token)
+
+			message, footer, err := V4Decrypt(tt.token, v4TestKey, []byte(tt.implicit))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.payload, string(message))
+			assert.Equal(t, tt.footer, string(footer))
+		})
+	}
+}
+
+func TestV4Encrypt(t *testing.T) {
+	message := []byte(`{"data":"this is a secret message","exp":"2022-01-01T00:00:00+00:00"}`)
+	footer := []byte(`{"kid":"trumanwong"}`)
+	implicit := []byte("implicit")
+	token, err := V4Encrypt(v4TestKey, message, footer, implicit)
+	assert.NoError(t, err)
+	decrypted, f, err := V4Decrypt(token, v4TestKey, implicit)
+	assert.NoError(t, err)
+	assert.Equal(t, message, decrypted)
+	assert.Equal(t, footer, f)
+
+	// random nonces
+	token1, err := V4Encrypt(v4TestKey, message, nil, nil)
+	assert.NoError(t, err)
+	token2, err := V4Encrypt(v4TestKey, message, nil, nil)
+	assert.NoError(t, err)
+	assert.NotEqual(t, token1, token2)
+
+	_, err = V4Encrypt(v4TestKey[:16], message, nil, nil)
+	assert.ErrorIs(t, err, ErrInvalidKey)
+}
+
+func TestV4Decrypt(t *testing.T) {
+	token, err := V4Encrypt(v4TestKey, []byte("trumanwong"), []byte("footer"), []byte("implicit"))
+	assert.NoError(t, err)
+
+	_, _, err = V4Decrypt(token, v4TestKey, nil)
+	assert.ErrorIs(t, err, ErrDecryption)
+	_, _, err = V4Decrypt(token, v4TestKey, []byte("other"))
+	assert.ErrorIs(t, err, ErrDecryption)
+
+	wrongKey := bytes.Repeat([]byte{1}, 32)
+	_, _, err = V4Decrypt(token, wrongKey, []byte("implicit"))
+	assert.ErrorIs(t, err, ErrDecryption)
+
+	// the footer is authenticated
+	body, _, err := decode(token, headerV4Local)
+	assert.NoError(t, err)
+	_, _, err = V4Decrypt(encode(headerV4Local, body, []byte("other")), v4TestKey, []byte("implicit"))
+	assert.ErrorIs(t, err, ErrDecryption)
+
+	body[nonceSize] ^= 1
+	_, _, err = V4Decrypt(encode(headerV4Local, body, []byte("footer")), v4TestKey, []byte("implicit"))
+	assert.ErrorIs(t, err, ErrDecryption)
+
+	_, _, err = V4Decrypt("v4.local.AAAA", v4TestKey, nil)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+	_, _, err = V4Decrypt("v3"+token[2:], v4TestKey, []byte("implicit"))
+	assert.ErrorIs(t, err, ErrInvalidToken)
+	_, _, err = V4Decrypt(token, v4TestKey[:31], []byte("implicit"))
+	assert.ErrorIs(t, err, ErrInvalidKey)
+}
+
+func ExampleV4Sign() {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		log.Fatal(err)
+	}
+	token, err := V4Sign(privateKey, []byte(`{"sub":"trumanwong"}`), nil, []byte("service-a"))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	message, _, err := V4Verify(token, publicKey, []byte("service-a"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(string(message))
+	// Output: {"sub":"trumanwong"}
+}
+
+func TestV4Sign(t *testing.T) {
+	privateKey := ed25519.NewKeyFromSeed(v4TestSeed)
+	message := []byte(`{"data":"this is a signed message","exp":"2022-01-01T00:00:00+00:00"}`)
+
+	// test vector 4-S-1
+	token, err := V4Sign(privateKey, message, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "v4.public.eyJkYXRhIjoidGhpcyBpcyBhIHNpZ25lZCBtZXNzYWdlIiwiZXhwIjoiMjAyMi0wMS0wMVQwMDowMDowMCswMDowMCJ9bg_XBBzds8lTZShVlwwKSgeKpLT3yukTw6JUz3W4h_ExsQV-P0V54zemZDcAxFaSeef1QlXEFtkqxT1ciiQEDA", token)
+
+	m, footer, err := V4Verify(token, privateKey.Public(), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, message, m)
+	assert.Nil(t, footer)
+
+	// pem keys
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	assert.NoError(t, err)
+	token, err = V4Sign(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), message, []byte("footer"), []byte("implicit"))
+	assert.NoError(t, err)
+	der, err = x509.MarshalPKIXPublicKey(privateKey.Public())
+	assert.NoError(t, err)
+	m, footer, err = V4Verify(token, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), []byte("implicit"))
+	assert.NoError(t, err)
+	assert.Equal(t, message, m)
+	assert.Equal(t, []byte("footer"), footer)
+
+	_, err = V4Sign(v4TestKey, message, nil, nil)
+	assert.ErrorIs(t, err, ErrInvalidKey)
+	_, err = V4Sign("key", message, nil, nil)
+	assert.ErrorIs(t, err, ErrInvalidKey)
+	_, err = V4Sign(privateKey[:32], message, nil, nil)
+	assert.ErrorIs(t, err, ErrInvalidKey)
+}
+
+func TestV4Verify(t *testing.T) {
+	privateKey := ed25519.NewKeyFromSeed(v4TestSeed)
+	publicKey := privateKey.Public()
+	token, err := V4Sign(privateKey, []byte("trumanwong"), []byte("footer"), []byte("implicit"))
+	assert.NoError(t, err)
+
+	_, _, err = V4Verify(token, publicKey, nil)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	otherKey, _, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	_, _, err = V4Verify(token, otherKey, []byte("implicit"))
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	body, _, err := decode(token, headerV4Public)
+	assert.NoError(t, err)
+	_, _, err = V4Verify(encode(headerV4Public, body, []byte("other")), publicKey, []byte("implicit"))
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+	body[0] ^= 1
+	_, _, err = V4Verify(encode(headerV4Public, body, []byte("footer")), publicKey, []byte("implicit"))
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	// a local token is never accepted as a public one
+	local, err := V4Encrypt(v4TestKey, []byte("trumanwong"), nil, nil)
+	assert.NoError(t, err)
+	_, _, err = V4Verify(local, publicKey, nil)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+	_, _, err = V4Verify("v4.public.AAAA", publicKey, nil)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	_, _, err = V4Verify(token, []byte("key"), []byte("implicit"))
+	assert.ErrorIs(t, err, ErrInvalidKey)
+	_, _, err = V4Verify(token, ed25519.PublicKey(v4TestKey[:16]), []byte("implicit"))
+	assert.ErrorIs(t, err, ErrInvalidKey)
+}
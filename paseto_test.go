@@ -0,0 +1,54 @@
+package cryptogo
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/trumanwong/cryptogo/paseto"
+	"log"
+	"testing"
+)
+
+func ExamplePasetoEncrypt() {
+	key := []byte("0123456789abcdef0123456789abcdef")
+	token, err := PasetoEncrypt(key, []byte(`{"username":"trumanwong"}`), []byte(`{"kid":"1"}`))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	message, footer, err := PasetoDecrypt(token, key)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(string(message), string(footer))
+	// Output: {"username":"trumanwong"} {"kid":"1"}
+}
+
+func TestPasetoDecrypt(t *testing.T) {
+	key := []byte("0123456789abcdef0123456789abcdef")
+	token, err := PasetoEncrypt(key, []byte("trumanwong"), nil)
+	assert.NoError(t, err)
+
+	_, _, err = PasetoDecrypt(token, []byte("abcdef0123456789abcdef0123456789"))
+	assert.ErrorIs(t, err, paseto.ErrDecryption)
+	_, _, err = PasetoDecrypt(token, []byte("key"))
+	assert.ErrorIs(t, err, paseto.ErrInvalidKey)
+}
+
+func TestPasetoSign(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	token, err := PasetoSign(privateKey, []byte("trumanwong"), []byte("footer"))
+	assert.NoError(t, err)
+
+	message, footer, err := PasetoVerify(token, publicKey)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("trumanwong"), message)
+	assert.Equal(t, []byte("footer"), footer)
+
+	otherKey, _, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	_, _, err = PasetoVerify(token, otherKey)
+	assert.ErrorIs(t, err, paseto.ErrInvalidSignature)
+}
@@ -0,0 +1,142 @@
+package cryptogo
+
+import (
+	"fmt"
+	"math/big"
+	"sync"
+)
+
+// radixEncoding converts bytes to and from a big-endian number in an
+// arbitrary base, as Base58 and Base62 do. Each leading zero byte is
+// written as the first character of the alphabet.
+//
+// Converting digit by digit is quadratic in the input size, so the number
+// is split recursively around powers base^(chunk*2^i) instead, which lets
+// math/big's Karatsuba multiplication and recursive division do the work.
+type radixEncoding struct {
+	name     string
+	alphabet string
+	decode   [256]byte
+	base     uint64
+	// chunk is the number of digits held by a uint64.
+	chunk int
+
+	mu     sync.Mutex
+	powers []*big.Int
+}
+
+func newRadixEncoding(name, alphabet string) *radixEncoding {
+	e := &radixEncoding{name: name, alphabet: alphabet, base: uint64(len(alphabet))}
+	for i := range e.decode {
+		e.decode[i] = 0xff
+	}
+	for i := 0; i < len(alphabet); i++ {
+		e.decode[alphabet[i]] = byte(i)
+	}
+	for p := e.base; p <= ^uint64(0)/e.base; p *= e.base {
+		e.chunk++
+	}
+	e.chunk++
+	return e
+}
+
+// power returns base^(chunk*2^i).
+func (e *radixEncoding) power(i int) *big.Int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if len(e.powers) == 0 {
+		p := uint64(1)
+		for j := 0; j < e.chunk; j++ {
+			p *= e.base
+		}
+		e.powers = append(e.powers, new(big.Int).SetUint64(p))
+	}
+	for len(e.powers) <= i {
+		last := e.powers[len(e.powers)-1]
+		e.powers = append(e.powers, new(big.Int).Mul(last, last))
+	}
+	return e.powers[i]
+}
+
+func (e *radixEncoding) Encode(src []byte) []byte {
+	zeros := 0
+	for zeros < len(src) && src[zeros] == 0 {
+		zeros++
+	}
+	dst := make([]byte, zeros, zeros+len(src)*138/100+1)
+	for i := range dst {
+		dst[i] = e.alphabet[0]
+	}
+	if zeros == len(src) {
+		return dst
+	}
+
+	x := new(big.Int).SetBytes(src[zeros:])
+	level := 0
+	for x.Cmp(e.power(level)) >= 0 {
+		level++
+	}
+	return e.encode(dst, x, level, false)
+}
+
+// encode appends the digits of x < power(level) to dst, padded to
+// chunk*2^level digits if pad is set.
+func (e *radixEncoding) encode(dst []byte, x *big.Int, level int, pad bool) []byte {
+	if level == 0 {
+		var buf [64]byte
+		i := len(buf)
+		for v := x.Uint64(); v > 0; v /= e.base {
+			i--
+			buf[i] = e.alphabet[v%e.base]
+		}
+		if pad {
+			for len(buf)-i < e.chunk {
+				i--
+				buf[i] = e.alphabet[0]
+			}
+		}
+		return append(dst, buf[i:]...)
+	}
+	q, r := new(big.Int).QuoRem(x, e.power(level-1), new(big.Int))
+	if pad || q.Sign() != 0 {
+		dst = e.encode(dst, q, level-1, pad)
+		pad = true
+	}
+	return e.encode(dst, r, level-1, pad)
+}
+
+func (e *radixEncoding) Decode(src []byte) ([]byte, error) {
+	for i, c := range src {
+		if e.decode[c] == 0xff {
+			return nil, fmt.Errorf("illegal %s data at input byte %d", e.name, i)
+		}
+	}
+	zeros := 0
+	for zeros < len(src) && src[zeros] == e.alphabet[0] {
+		zeros++
+	}
+	dst := make([]byte, zeros)
+	if zeros == len(src) {
+		return dst, nil
+	}
+	return append(dst, e.value(src[zeros:]).Bytes()...), nil
+}
+
+// value returns the number written by the digits of src.
+func (e *radixEncoding) value(src []byte) *big.Int {
+	if len(src) <= e.chunk {
+		var v uint64
+		for _, c := range src {
+			v = v*e.base + uint64(e.decode[c])
+		}
+		return new(big.Int).SetUint64(v)
+	}
+	// split off the largest chunk*2^level low digits
+	level, n := 0, e.chunk
+	for 2*n < len(src) {
+		level, n = level+1, 2*n
+	}
+	high := e.value(src[:len(src)-n])
+	high.Mul(high, e.power(level))
+	return high.Add(high, e.value(src[len(src)-n:]))
+}
@@ -0,0 +1,64 @@
+package cryptogo
+
+import (
+	"crypto/rand"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"testing"
+)
+
+// naiveRadixEncode is the textbook digit by digit conversion.
+func naiveRadixEncode(e *radixEncoding, src []byte) string {
+	zeros := 0
+	for zeros < len(src) && src[zeros] == 0 {
+		zeros++
+	}
+	x := new(big.Int).SetBytes(src)
+	base, r := big.NewInt(int64(e.base)), new(big.Int)
+	var digits []byte
+	for x.Sign() > 0 {
+		x.QuoRem(x, base, r)
+		digits = append([]byte{e.alphabet[r.Int64()]}, digits...)
+	}
+	for i := 0; i < zeros; i++ {
+		digits = append([]byte{e.alphabet[0]}, digits...)
+	}
+	return string(digits)
+}
+
+func TestRadixEncoding(t *testing.T) {
+	for _, e := range []*radixEncoding{base58Bitcoin, base62} {
+		t.Run(e.name, func(t *testing.T) {
+			assert.Equal(t, 10, e.chunk)
+			// around the chunk boundaries of the recursion
+			for _, size := range []int{1, 7, 8, 9, 15, 16, 17, 31, 32, 33, 100, 257, 1000} {
+				src := make([]byte, size)
+				_, err := rand.Read(src)
+				assert.NoError(t, err)
+				src[0] = 0
+				encoded := e.Encode(src)
+				assert.Equal(t, naiveRadixEncode(e, src), string(encoded), size)
+				decoded, err := e.Decode(encoded)
+				assert.NoError(t, err)
+				assert.Equal(t, src, decoded, size)
+			}
+
+			// powers of the base have zero digits in every chunk
+			src := new(big.Int).Exp(big.NewInt(int64(e.base)), big.NewInt(95), nil).Bytes()
+			encoded := e.Encode(src)
+			assert.Equal(t, naiveRadixEncode(e, src), string(encoded))
+			decoded, err := e.Decode(encoded)
+			assert.NoError(t, err)
+			assert.Equal(t, src, decoded)
+		})
+	}
+}
+
+func TestRadixEncodingLarge(t *testing.T) {
+	src := make([]byte, 1<<18)
+	_, err := rand.Read(src)
+	assert.NoError(t, err)
+	decoded, err := Base58Decode(Base58Encode(src))
+	assert.NoError(t, err)
+	assert.Equal(t, src, decoded)
+}
@@ -0,0 +1,275 @@
+package rsa
+
+import (
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+
+	"github.com/emmansun/gmsm/pkcs8"
+)
+
+// MinKeyBits is the smallest modulus size accepted by the default key
+// validation policy.
+const MinKeyBits = 2048
+
+// marshalPrivateKey encode private key to pem with format(PKCS1 or PKCS8).
+func marshalPrivateKey(key *rsa.PrivateKey, format keyFormat) ([]byte, error) {
+	switch format {
+	case PKCS1:
+		return pem.EncodeToMemory(&pem.Block{
+			Type:  "RSA PRIVATE KEY",
+			Bytes: x509.MarshalPKCS1PrivateKey(key),
+		}), nil
+	case PKCS8:
+		privateKeyBytes, err := x509.MarshalPKCS8PrivateKey(key)
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("generate pkcs8 private key fail, %s", err))
+		}
+		return pem.EncodeToMemory(&pem.Block{
+			Type:  "PRIVATE KEY",
+			Bytes: privateKeyBytes,
+		}), nil
+	}
+	return nil, errors.New("invalid key format")
+}
+
+// marshalPublicKey encode public key to pem with format(PKCS1 or PKCS8).
+func marshalPublicKey(key *rsa.PublicKey, format keyFormat) ([]byte, error) {
+	switch format {
+	case PKCS1:
+		return pem.EncodeToMemory(&pem.Block{
+			Type:  "RSA PUBLIC KEY",
+			Bytes: x509.MarshalPKCS1PublicKey(key),
+		}), nil
+	case PKCS8:
+		publicKeyBytes, err := x509.MarshalPKIXPublicKey(key)
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("generate public key fail, %s", err))
+		}
+		return pem.EncodeToMemory(&pem.Block{
+			Type:  "PUBLIC KEY",
+			Bytes: publicKeyBytes,
+		}), nil
+	}
+	return nil, errors.New("invalid key format")
+}
+
+// GenerateEncryptedKeyPair Generate rsa key pair with bits, the private key
+// is password protected PKCS8 (ENCRYPTED PRIVATE KEY) and the public key is
+// PKIX (PUBLIC KEY).
+func GenerateEncryptedKeyPair(bits int, password []byte) (privateKey, publicKey []byte, err error) {
+	privateKey, publicKey, err = GenerateKeyPair(bits, PKCS8)
+	if err != nil {
+		return nil, nil, err
+	}
+	privateKey, err = EncryptPrivateKey(privateKey, password)
+	if err != nil {
+		return nil, nil, err
+	}
+	return privateKey, publicKey, nil
+}
+
+// EncryptPrivateKey protect a PKCS1 or PKCS8 private key pem with password,
+// return ENCRYPTED PRIVATE KEY pem (PBES2, PBKDF2-SHA256, AES-256-CBC).
+func EncryptPrivateKey(privateKey, password []byte) ([]byte, error) {
+	if len(password) == 0 {
+		return nil, errors.New("password is empty")
+	}
+	key, err := parsePrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	der, err := pkcs8.MarshalPrivateKey(key, password, nil)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{
+		Type:  "ENCRYPTED PRIVATE KEY",
+		Bytes: der,
+	}), nil
+}
+
+// DecryptPrivateKey decrypt an ENCRYPTED PRIVATE KEY pem with password,
+// return private key pem with format(PKCS1 or PKCS8).
+func DecryptPrivateKey(privateKey, password []byte, format keyFormat) ([]byte, error) {
+	block, _ := pem.Decode(privateKey)
+	if block == nil || block.Type != "ENCRYPTED PRIVATE KEY" {
+		return nil, errors.New("key is invalid format")
+	}
+	key, err := pkcs8.ParsePKCS8PrivateKeyRSA(block.Bytes, password)
+	if err != nil {
+		return nil, err
+	}
+	return marshalPrivateKey(key, format)
+}
+
+// ConvertPrivateKey convert a PKCS1 or PKCS8 private key pem to format.
+func ConvertPrivateKey(privateKey []byte, format keyFormat) ([]byte, error) {
+	key, err := parsePrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	return marshalPrivateKey(key, format)
+}
+
+// ConvertPublicKey convert a PKCS1 or PKIX public key pem to format.
+func ConvertPublicKey(publicKey []byte, format keyFormat) ([]byte, error) {
+	key, err := parsePublicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	return marshalPublicKey(key, format)
+}
+
+// ExtractPublicKey return the public key pem with format of a private key.
+func ExtractPublicKey(privateKey []byte, format keyFormat) ([]byte, error) {
+	key, err := parsePrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	return marshalPublicKey(&key.PublicKey, format)
+}
+
+// PublicKeyFromCertificate return the rsa public key pem with format of a
+// pem or der encoded X.509 certificate.
+func PublicKeyFromCertificate(cert []byte, format keyFormat) ([]byte, error) {
+	if block, _ := pem.Decode(cert); block != nil {
+		if block.Type != "CERTIFICATE" {
+			return nil, errors.New("certificate is invalid format")
+		}
+		cert = block.Bytes
+	}
+	key, err := parseCertificate(cert)
+	if err != nil {
+		return nil, err
+	}
+	return marshalPublicKey(key, format)
+}
+
+func parseCertificate(der []byte) (*rsa.PublicKey, error) {
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.New("certificate is not a rsa certificate")
+	}
+	return key, nil
+}
+
+// KeyToBase64 return the base64 encoded der of a pem key without the pem
+// header and footer, as many SDKs expect.
+func KeyToBase64(key []byte) (string, error) {
+	block, _ := pem.Decode(key)
+	if block == nil {
+		return "", errors.New("key is invalid format")
+	}
+	return base64.StdEncoding.EncodeToString(block.Bytes), nil
+}
+
+// PrivateKeyFromBase64 return the private key pem of a base64 encoded PKCS1
+// or PKCS8 der, the format is detected.
+func PrivateKeyFromBase64(key string) ([]byte, error) {
+	der, err := base64.StdEncoding.DecodeString(key)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = x509.ParsePKCS1PrivateKey(der); err == nil {
+		return pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: der}), nil
+	}
+	if k, err := x509.ParsePKCS8PrivateKey(der); err == nil {
+		if _, ok := k.(*rsa.PrivateKey); ok {
+			return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+		}
+	}
+	return nil, errors.New("key is invalid private key")
+}
+
+// PublicKeyFromBase64 return the public key pem of a base64 encoded PKCS1
+// or PKIX der, the format is detected.
+func PublicKeyFromBase64(key string) ([]byte, error) {
+	der, err := base64.StdEncoding.DecodeString(key)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = x509.ParsePKCS1PublicKey(der); err == nil {
+		return pem.EncodeToMemory(&pem.Block{Type: "RSA PUBLIC KEY", Bytes: der}), nil
+	}
+	if k, err := x509.ParsePKIXPublicKey(der); err == nil {
+		if _, ok := k.(*rsa.PublicKey); ok {
+			return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), nil
+		}
+	}
+	return nil, errors.New("key is invalid public key")
+}
+
+// Fingerprint return the hex encoded SHA-256 of the PKIX der of a public
+// key, or of the public part of a private key.
+func Fingerprint(key []byte) (string, error) {
+	pub, err := parsePublicKey(key)
+	if err != nil {
+		priv, privErr := parsePrivateKey(key)
+		if privErr != nil {
+			return "", err
+		}
+		pub = &priv.PublicKey
+	}
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(der)), nil
+}
+
+// ParsePrivateKey parse a PKCS1 or PKCS8 private key pem, for use with
+// crypto/rsa or other packages such as jwt.
+func ParsePrivateKey(privateKey []byte) (*rsa.PrivateKey, error) {
+	return parsePrivateKey(privateKey)
+}
+
+// ParsePublicKey parse a PKCS1, PKIX or X.509 certificate public key pem,
+// for use with crypto/rsa or other packages such as jwt.
+func ParsePublicKey(publicKey []byte) (*rsa.PublicKey, error) {
+	return parsePublicKey(publicKey)
+}
+
+// Validate check the private key is consistent and its modulus is at least
+// minBits long, minBits <= 0 means MinKeyBits.
+func Validate(privateKey []byte, minBits int) error {
+	key, err := parsePrivateKey(privateKey)
+	if err != nil {
+		return err
+	}
+	if err = key.Validate(); err != nil {
+		return err
+	}
+	return checkKeySize(&key.PublicKey, minBits)
+}
+
+// ValidatePublicKey check the public key modulus is at least minBits long,
+// minBits <= 0 means MinKeyBits.
+func ValidatePublicKey(publicKey []byte, minBits int) error {
+	key, err := parsePublicKey(publicKey)
+	if err != nil {
+		return err
+	}
+	if key.E < 3 || key.E&1 == 0 {
+		return errors.New("key has invalid public exponent")
+	}
+	return checkKeySize(key, minBits)
+}
+
+func checkKeySize(key *rsa.PublicKey, minBits int) error {
+	if minBits <= 0 {
+		minBits = MinKeyBits
+	}
+	if bits := key.N.BitLen(); bits < minBits {
+		return fmt.Errorf("key size %d is less than %d bits", bits, minBits)
+	}
+	return nil
+}
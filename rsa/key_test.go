@@ -0,0 +1,165 @@
+package rsa
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"log"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func ExampleFingerprint() {
+	publicKey := []byte(`-----BEGIN PUBLIC KEY-----
+MFwwDQYJKoZIhvcNAQEBBQADSwAwSAJBAMCaj0ke3F7nUNEL71eapLlB1jw8MQEg
+6uXMcVT3Ttn4HzibNrOgQe31c4KXdGXnIyoWyIg7Q0ZEdP2FTQX0o+cCAwEAAQ==
+-----END PUBLIC KEY-----
+`)
+	fingerprint, err := Fingerprint(publicKey)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(fingerprint)
+	// Output: 452aa8611ff0bd4a16a30f8c90507aeb1ebec35faef2536e7d381c02bba79951
+}
+
+func TestEncryptPrivateKey(t *testing.T) {
+	priKey, pubKey, err := GenerateEncryptedKeyPair(2048, []byte("password"))
+	assert.NoError(t, err)
+	block, _ := pem.Decode(priKey)
+	assert.Equal(t, "ENCRYPTED PRIVATE KEY", block.Type)
+
+	_, err = DecryptPrivateKey(priKey, []byte("wrong"), PKCS8)
+	assert.Error(t, err)
+
+	for _, format := range []keyFormat{PKCS1, PKCS8} {
+		plain, err := DecryptPrivateKey(priKey, []byte("password"), format)
+		assert.NoError(t, err)
+
+		src := []byte("trumanwong")
+		dst, err := Encrypt(src, pubKey)
+		assert.NoError(t, err)
+		dst, err = Decrypt(dst, plain)
+		assert.NoError(t, err)
+		assert.Equal(t, src, dst)
+	}
+
+	_, err = EncryptPrivateKey(priKey, nil)
+	assert.Error(t, err)
+}
+
+func TestConvertKey(t *testing.T) {
+	priKey, pubKey, err := GenerateKeyPair(2048, PKCS1)
+	assert.NoError(t, err)
+
+	pkcs8PriKey, err := ConvertPrivateKey(priKey, PKCS8)
+	assert.NoError(t, err)
+	block, _ := pem.Decode(pkcs8PriKey)
+	assert.Equal(t, "PRIVATE KEY", block.Type)
+	pkcs1PriKey, err := ConvertPrivateKey(pkcs8PriKey, PKCS1)
+	assert.NoError(t, err)
+	assert.Equal(t, priKey, pkcs1PriKey)
+
+	pkixPubKey, err := ConvertPublicKey(pubKey, PKCS8)
+	assert.NoError(t, err)
+	pkcs1PubKey, err := ConvertPublicKey(pkixPubKey, PKCS1)
+	assert.NoError(t, err)
+	assert.Equal(t, pubKey, pkcs1PubKey)
+
+	extracted, err := ExtractPublicKey(pkcs8PriKey, PKCS1)
+	assert.NoError(t, err)
+	assert.Equal(t, pubKey, extracted)
+
+	_, err = ConvertPrivateKey(priKey, "pkcs12")
+	assert.Error(t, err)
+}
+
+func TestPublicKeyFromCertificate(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "trumanwong"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+
+	pubKey, err := PublicKeyFromCertificate(cert, PKCS8)
+	assert.NoError(t, err)
+	derPubKey, err := PublicKeyFromCertificate(der, PKCS8)
+	assert.NoError(t, err)
+	assert.Equal(t, pubKey, derPubKey)
+
+	priKey, err := marshalPrivateKey(key, PKCS8)
+	assert.NoError(t, err)
+	src := []byte("trumanwong")
+	dst, err := Encrypt(src, cert)
+	assert.NoError(t, err)
+	dst, err = Decrypt(dst, priKey)
+	assert.NoError(t, err)
+	assert.Equal(t, src, dst)
+}
+
+func TestKeyBase64(t *testing.T) {
+	for _, format := range []keyFormat{PKCS1, PKCS8} {
+		priKey, pubKey, err := GenerateKeyPair(2048, format)
+		assert.NoError(t, err)
+
+		s, err := KeyToBase64(priKey)
+		assert.NoError(t, err)
+		ret, err := PrivateKeyFromBase64(s)
+		assert.NoError(t, err)
+		assert.Equal(t, priKey, ret)
+
+		s, err = KeyToBase64(pubKey)
+		assert.NoError(t, err)
+		ret, err = PublicKeyFromBase64(s)
+		assert.NoError(t, err)
+		assert.Equal(t, pubKey, ret)
+
+		_, err = PrivateKeyFromBase64(s)
+		assert.Error(t, err)
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	priKey, pubKey, err := GenerateKeyPair(2048, PKCS1)
+	assert.NoError(t, err)
+	pkixPubKey, err := ConvertPublicKey(pubKey, PKCS8)
+	assert.NoError(t, err)
+
+	f1, err := Fingerprint(pubKey)
+	assert.NoError(t, err)
+	f2, err := Fingerprint(pkixPubKey)
+	assert.NoError(t, err)
+	f3, err := Fingerprint(priKey)
+	assert.NoError(t, err)
+	assert.Len(t, f1, 64)
+	assert.Equal(t, f1, f2)
+	assert.Equal(t, f1, f3)
+}
+
+func TestValidate(t *testing.T) {
+	priKey, pubKey, err := GenerateKeyPair(1024, PKCS8)
+	assert.NoError(t, err)
+
+	assert.Error(t, Validate(priKey, 0))
+	assert.NoError(t, Validate(priKey, 1024))
+	assert.Error(t, ValidatePublicKey(pubKey, MinKeyBits))
+	assert.NoError(t, ValidatePublicKey(pubKey, 1024))
+
+	key, err := parsePrivateKey(priKey)
+	assert.NoError(t, err)
+	key.D = new(big.Int).Add(key.D, big.NewInt(2))
+	broken, err := marshalPrivateKey(key, PKCS1)
+	assert.NoError(t, err)
+	assert.Error(t, Validate(broken, 1024))
+}
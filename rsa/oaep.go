@@ -0,0 +1,253 @@
+package rsa
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"math/big"
+
+	"github.com/emmansun/gmsm/sm3"
+)
+
+// Hash is a hash function usable for OAEP and MGF1.
+type Hash string
+
+const (
+	SHA1   Hash = "sha1"
+	SHA256 Hash = "sha256"
+	SHA512 Hash = "sha512"
+	SM3    Hash = "sm3"
+)
+
+// New returns a new hash.Hash computing the hash h.
+func (h Hash) New() (hash.Hash, error) {
+	switch h {
+	case SHA1:
+		return sha1.New(), nil
+	case SHA256:
+		return sha256.New(), nil
+	case SHA512:
+		return sha512.New(), nil
+	case SM3:
+		return sm3.New(), nil
+	}
+	return nil, errors.New("invalid hash")
+}
+
+// cryptoHash returns the crypto.Hash of h, SM3 has none.
+func (h Hash) cryptoHash() (crypto.Hash, bool) {
+	switch h {
+	case SHA1:
+		return crypto.SHA1, true
+	case SHA256:
+		return crypto.SHA256, true
+	case SHA512:
+		return crypto.SHA512, true
+	}
+	return 0, false
+}
+
+// scheme is the padding scheme of an RSA operation, PKCS1v15 applies to
+// both encryption and signatures, OAEP to encryption and PSS to signatures.
+type scheme string
+
+const (
+	PKCS1v15 scheme = "pkcs1v15"
+	OAEP     scheme = "oaep"
+	PSS      scheme = "pss"
+)
+
+// EncryptOptions selects the padding scheme used by EncryptWithOptions and
+// DecryptWithOptions. Scheme defaults to OAEP. Hash, MGFHash and Label are
+// only used by OAEP, Hash defaults to SHA256 and MGFHash defaults to Hash.
+// An MGFHash different from Hash must not be SM3, which crypto/rsa does not
+// support.
+type EncryptOptions struct {
+	Scheme  scheme
+	Hash    Hash
+	MGFHash Hash
+	Label   []byte
+}
+
+// defaultEncryptOptions is used when nil options are given.
+var defaultEncryptOptions = &EncryptOptions{Scheme: OAEP, Hash: SHA256}
+
+func (opts *EncryptOptions) scheme() scheme {
+	if opts.Scheme == "" {
+		return OAEP
+	}
+	return opts.Scheme
+}
+
+func (opts *EncryptOptions) hashes() (Hash, Hash) {
+	h := opts.Hash
+	if h == "" {
+		h = SHA256
+	}
+	mgf := opts.MGFHash
+	if mgf == "" {
+		mgf = h
+	}
+	return h, mgf
+}
+
+// oaepOptions returns the rsa.OAEPOptions of a label hash and MGF1 hash that
+// differ.
+func (opts *EncryptOptions) oaepOptions(h, mgf Hash) (*rsa.OAEPOptions, error) {
+	ch, ok := h.cryptoHash()
+	if !ok {
+		return nil, errors.New("invalid hash, SM3 needs the same MGF1 hash")
+	}
+	cm, ok := mgf.cryptoHash()
+	if !ok {
+		return nil, errors.New("invalid MGF1 hash, SM3 needs the same hash")
+	}
+	return &rsa.OAEPOptions{Hash: ch, MGFHash: cm, Label: opts.Label}, nil
+}
+
+// EncryptWithOptions RSA Encrypt with the scheme in opts, nil opts means
+// OAEP with SHA256.
+func EncryptWithOptions(src, publicKey []byte, opts *EncryptOptions) ([]byte, error) {
+	rsaPublicKey, err := parsePublicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	return encrypt(rsaPublicKey, src, opts)
+}
+
+// DecryptWithOptions RSA Decrypt with the scheme in opts, nil opts means
+// OAEP with SHA256.
+func DecryptWithOptions(src, privateKey []byte, opts *EncryptOptions) ([]byte, error) {
+	rsaPrivateKey, err := parsePrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	return decrypt(rsaPrivateKey, src, opts)
+}
+
+// EncryptOAEP RSA-OAEP Encrypt with hash used for both OAEP and MGF1.
+func EncryptOAEP(src, publicKey []byte, hash Hash, label []byte) ([]byte, error) {
+	return EncryptWithOptions(src, publicKey, &EncryptOptions{Scheme: OAEP, Hash: hash, Label: label})
+}
+
+// DecryptOAEP RSA-OAEP Decrypt with hash used for both OAEP and MGF1.
+func DecryptOAEP(src, privateKey []byte, hash Hash, label []byte) ([]byte, error) {
+	return DecryptWithOptions(src, privateKey, &EncryptOptions{Scheme: OAEP, Hash: hash, Label: label})
+}
+
+func encrypt(pub *rsa.PublicKey, src []byte, opts *EncryptOptions) ([]byte, error) {
+	if opts == nil {
+		opts = defaultEncryptOptions
+	}
+	switch opts.scheme() {
+	case PKCS1v15:
+		return rsa.EncryptPKCS1v15(rand.Reader, pub, src)
+	case OAEP:
+		h, mgf := opts.hashes()
+		hashFunc, err := h.New()
+		if err != nil {
+			return nil, err
+		}
+		if h == mgf {
+			return rsa.EncryptOAEP(hashFunc, rand.Reader, pub, src, opts.Label)
+		}
+		if _, err = opts.oaepOptions(h, mgf); err != nil {
+			return nil, err
+		}
+		mgfFunc, err := mgf.New()
+		if err != nil {
+			return nil, err
+		}
+		return encryptOAEP(pub, hashFunc, mgfFunc, src, opts.Label)
+	}
+	return nil, fmt.Errorf("%w: %q is not an encrypt scheme", ErrInvalidScheme, opts.Scheme)
+}
+
+func decrypt(priv *rsa.PrivateKey, src []byte, opts *EncryptOptions) ([]byte, error) {
+	if opts == nil {
+		opts = defaultEncryptOptions
+	}
+	switch opts.scheme() {
+	case PKCS1v15:
+		return rsa.DecryptPKCS1v15(rand.Reader, priv, src)
+	case OAEP:
+		h, mgf := opts.hashes()
+		hashFunc, err := h.New()
+		if err != nil {
+			return nil, err
+		}
+		if h == mgf {
+			return rsa.DecryptOAEP(hashFunc, rand.Reader, priv, src, opts.Label)
+		}
+		oaepOpts, err := opts.oaepOptions(h, mgf)
+		if err != nil {
+			return nil, err
+		}
+		return priv.Decrypt(rand.Reader, src, oaepOpts)
+	}
+	return nil, fmt.Errorf("%w: %q is not an encrypt scheme", ErrInvalidScheme, opts.Scheme)
+}
+
+// encryptOAEP implements RSAES-OAEP-ENCRYPT (RFC 8017, 7.1.1) for the case
+// where the label hash and the MGF1 hash differ, which rsa.EncryptOAEP does
+// not support. It only uses the public key.
+func encryptOAEP(pub *rsa.PublicKey, hash, mgfHash hash.Hash, msg, label []byte) ([]byte, error) {
+	k := pub.Size()
+	hLen := hash.Size()
+	if len(msg) > k-2*hLen-2 {
+		return nil, rsa.ErrMessageTooLong
+	}
+
+	hash.Reset()
+	hash.Write(label)
+	lHash := hash.Sum(nil)
+
+	em := make([]byte, k)
+	seed := em[1 : 1+hLen]
+	db := em[1+hLen:]
+	copy(db[:hLen], lHash)
+	db[len(db)-len(msg)-1] = 1
+	copy(db[len(db)-len(msg):], msg)
+
+	if _, err := rand.Read(seed); err != nil {
+		return nil, err
+	}
+	mgf1XOR(db, mgfHash, seed)
+	mgf1XOR(seed, mgfHash, db)
+
+	return encryptRaw(pub, em), nil
+}
+
+// mgf1XOR XORs the bytes in out with a mask generated using the MGF1 function
+// specified in RFC 8017, B.2.1.
+func mgf1XOR(out []byte, hash hash.Hash, seed []byte) {
+	var counter [4]byte
+	var digest []byte
+	done := 0
+	for done < len(out) {
+		hash.Reset()
+		hash.Write(seed)
+		hash.Write(counter[:])
+		digest = hash.Sum(digest[:0])
+		for i := 0; i < len(digest) && done < len(out); i++ {
+			out[done] ^= digest[i]
+			done++
+		}
+		binary.BigEndian.PutUint32(counter[:], binary.BigEndian.Uint32(counter[:])+1)
+	}
+}
+
+// encryptRaw applies the RSA public key operation to em, which must be
+// exactly pub.Size() bytes long.
+func encryptRaw(pub *rsa.PublicKey, em []byte) []byte {
+	m := new(big.Int).SetBytes(em)
+	c := m.Exp(m, big.NewInt(int64(pub.E)), pub.N)
+	return c.FillBytes(make([]byte, pub.Size()))
+}
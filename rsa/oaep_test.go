@@ -0,0 +1,89 @@
+package rsa
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"log"
+	"testing"
+)
+
+func ExampleEncryptOAEP() {
+	priKey, pubKey, err := GenerateKeyPair(2048, PKCS8)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	src := []byte("trumanwong")
+	dst, err := EncryptOAEP(src, pubKey, SHA256, []byte("label"))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	dst, err = DecryptOAEP(dst, priKey, SHA256, []byte("label"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(string(dst))
+	// Output: trumanwong
+}
+
+func TestEncryptWithOptions(t *testing.T) {
+	priKey, pubKey, err := GenerateKeyPair(2048, PKCS1)
+	assert.NoError(t, err)
+
+	src := []byte("trumanwong")
+	tests := []*EncryptOptions{
+		nil,
+		{},
+		{Scheme: PKCS1v15},
+		{Scheme: OAEP},
+		{Scheme: OAEP, Hash: SHA1},
+		{Scheme: OAEP, Hash: SHA512, Label: []byte("label")},
+		{Scheme: OAEP, Hash: SM3, Label: []byte("label")},
+		{Scheme: OAEP, Hash: SHA256, MGFHash: SHA1},
+		{Scheme: OAEP, Hash: SHA512, MGFHash: SHA256, Label: []byte("label")},
+		{Hash: SM3, MGFHash: SM3},
+	}
+	for _, opts := range tests {
+		t.Run(fmt.Sprintf("%+v", opts), func(t *testing.T) {
+			dst, err := EncryptWithOptions(src, pubKey, opts)
+			assert.NoError(t, err)
+			dst, err = DecryptWithOptions(dst, priKey, opts)
+			assert.NoError(t, err)
+			assert.Equal(t, src, dst)
+		})
+	}
+
+	t.Run("WrongLabel", func(t *testing.T) {
+		for _, mgf := range []Hash{SHA256, SHA1} {
+			dst, err := EncryptWithOptions(src, pubKey, &EncryptOptions{Scheme: OAEP, MGFHash: mgf, Label: []byte("a")})
+			assert.NoError(t, err)
+			_, err = DecryptWithOptions(dst, priKey, &EncryptOptions{Scheme: OAEP, MGFHash: mgf, Label: []byte("b")})
+			assert.Error(t, err)
+		}
+	})
+
+	t.Run("ZeroScheme", func(t *testing.T) {
+		dst, err := EncryptWithOptions(src, pubKey, &EncryptOptions{})
+		assert.NoError(t, err)
+		dst, err = DecryptWithOptions(dst, priKey, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, src, dst)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		for _, opts := range []*EncryptOptions{
+			{Scheme: "rsa"},
+			{Scheme: OAEP, Hash: "md4"},
+			{Scheme: OAEP, Hash: SM3, MGFHash: SHA256},
+			{Scheme: OAEP, Hash: SHA512, MGFHash: SM3},
+		} {
+			_, err := EncryptWithOptions(src, pubKey, opts)
+			assert.Error(t, err)
+			_, err = DecryptWithOptions(make([]byte, 256), priKey, opts)
+			assert.Error(t, err)
+		}
+		_, err := EncryptWithOptions(src, pubKey, &EncryptOptions{Scheme: PSS})
+		assert.ErrorIs(t, err, ErrInvalidScheme)
+	})
+}
@@ -0,0 +1,70 @@
+package rsa
+
+import (
+	"crypto/rsa"
+	"errors"
+
+	"github.com/trumanwong/cryptogo/openssh"
+)
+
+// AuthorizedKey convert a public key pem to an OpenSSH authorized_keys line
+// ("ssh-rsa AAAA... comment").
+func AuthorizedKey(publicKey []byte, comment string) ([]byte, error) {
+	key, err := parsePublicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	return openssh.MarshalAuthorizedKey(key, comment)
+}
+
+// ParseAuthorizedKey convert an OpenSSH ssh-rsa authorized_keys line to a
+// public key pem with format, return the pem and the comment.
+func ParseAuthorizedKey(line []byte, format keyFormat) ([]byte, string, error) {
+	pub, comment, err := openssh.ParseAuthorizedKey(line)
+	if err != nil {
+		return nil, "", err
+	}
+	key, ok := pub.(*rsa.PublicKey)
+	if !ok {
+		return nil, "", errors.New("key is invalid public key")
+	}
+	publicKey, err := marshalPublicKey(key, format)
+	if err != nil {
+		return nil, "", err
+	}
+	return publicKey, comment, nil
+}
+
+// OpenSSHPrivateKey convert a private key pem to an OPENSSH PRIVATE KEY pem,
+// encrypted with passphrase unless it is empty.
+func OpenSSHPrivateKey(privateKey []byte, comment string, passphrase []byte) ([]byte, error) {
+	key, err := parsePrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	return openssh.MarshalPrivateKey(key, comment, passphrase)
+}
+
+// ParseOpenSSHPrivateKey convert an OPENSSH PRIVATE KEY pem, decrypted with
+// passphrase if needed, to a private key pem with format.
+func ParseOpenSSHPrivateKey(privateKey, passphrase []byte, format keyFormat) ([]byte, error) {
+	priv, err := openssh.ParsePrivateKey(privateKey, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := priv.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("key is invalid private key")
+	}
+	return marshalPrivateKey(key, format)
+}
+
+// SSHFingerprint return the SHA256 fingerprint of a public key pem as
+// ssh-keygen -l prints it.
+func SSHFingerprint(publicKey []byte) (string, error) {
+	key, err := parsePublicKey(publicKey)
+	if err != nil {
+		return "", err
+	}
+	return openssh.Fingerprint(key)
+}
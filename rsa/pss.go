@@ -0,0 +1,132 @@
+package rsa
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const (
+	// PSSSaltLengthAuto causes the salt in a PSS signature to be as large
+	// as possible when signing, and to be auto-detected when verifying.
+	PSSSaltLengthAuto = rsa.PSSSaltLengthAuto
+	// PSSSaltLengthEqualsHash causes the salt length to equal the length
+	// of the hash used in the signature.
+	PSSSaltLengthEqualsHash = rsa.PSSSaltLengthEqualsHash
+)
+
+// ErrInvalidScheme is returned for a Scheme the operation doesn't support.
+var ErrInvalidScheme = errors.New("rsa: invalid scheme")
+
+// SignOptions selects the signature scheme used by SignWithOptions,
+// VerifyWithOptions, SignReader and VerifyReader. Scheme defaults to PSS and
+// Hash to SHA256. SaltLength is only used by PSS, where zero means
+// PSSSaltLengthAuto. Nil options mean PSS with SHA256.
+type SignOptions struct {
+	Scheme     scheme
+	Hash       crypto.Hash
+	SaltLength int
+}
+
+// defaultSignOptions is used when nil options are given.
+var defaultSignOptions = &SignOptions{Scheme: PSS, Hash: crypto.SHA256}
+
+func (opts *SignOptions) scheme() scheme {
+	if opts.Scheme == "" {
+		return defaultSignOptions.Scheme
+	}
+	return opts.Scheme
+}
+
+func (opts *SignOptions) hash() crypto.Hash {
+	if opts.Hash == 0 {
+		return defaultSignOptions.Hash
+	}
+	return opts.Hash
+}
+
+// SignWithOptions rsa sign with the scheme in opts.
+func SignWithOptions(src, privateKey []byte, opts *SignOptions) ([]byte, error) {
+	return SignReader(bytes.NewReader(src), privateKey, opts)
+}
+
+// VerifyWithOptions rsa verify with the scheme in opts.
+func VerifyWithOptions(src, sign, publicKey []byte, opts *SignOptions) error {
+	return VerifyReader(bytes.NewReader(src), sign, publicKey, opts)
+}
+
+// SignPSS rsa sign with RSASSA-PSS.
+func SignPSS(src, privateKey []byte, hash crypto.Hash, saltLength int) ([]byte, error) {
+	return SignWithOptions(src, privateKey, &SignOptions{Scheme: PSS, Hash: hash, SaltLength: saltLength})
+}
+
+// VerifyPSS rsa verify with RSASSA-PSS.
+func VerifyPSS(src, sign, publicKey []byte, hash crypto.Hash, saltLength int) error {
+	return VerifyWithOptions(src, sign, publicKey, &SignOptions{Scheme: PSS, Hash: hash, SaltLength: saltLength})
+}
+
+// SignReader rsa sign the content read from r, which is hashed as it is
+// read so large files don't need to be held in memory.
+func SignReader(r io.Reader, privateKey []byte, opts *SignOptions) ([]byte, error) {
+	if opts == nil {
+		opts = defaultSignOptions
+	}
+	rsaPrivateKey, err := parsePrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	hashed, err := hashReader(r, opts.hash())
+	if err != nil {
+		return nil, err
+	}
+	switch opts.scheme() {
+	case PKCS1v15:
+		return rsa.SignPKCS1v15(rand.Reader, rsaPrivateKey, opts.hash(), hashed)
+	case PSS:
+		return rsa.SignPSS(rand.Reader, rsaPrivateKey, opts.hash(), hashed, opts.pssOptions())
+	}
+	return nil, fmt.Errorf("%w: %q is not a sign scheme", ErrInvalidScheme, opts.Scheme)
+}
+
+// VerifyReader rsa verify the content read from r.
+func VerifyReader(r io.Reader, sign, publicKey []byte, opts *SignOptions) error {
+	if opts == nil {
+		opts = defaultSignOptions
+	}
+	rsaPublicKey, err := parsePublicKey(publicKey)
+	if err != nil {
+		return err
+	}
+
+	hashed, err := hashReader(r, opts.hash())
+	if err != nil {
+		return err
+	}
+	switch opts.scheme() {
+	case PKCS1v15:
+		return rsa.VerifyPKCS1v15(rsaPublicKey, opts.hash(), hashed, sign)
+	case PSS:
+		return rsa.VerifyPSS(rsaPublicKey, opts.hash(), hashed, sign, opts.pssOptions())
+	}
+	return fmt.Errorf("%w: %q is not a sign scheme", ErrInvalidScheme, opts.Scheme)
+}
+
+func (opts *SignOptions) pssOptions() *rsa.PSSOptions {
+	return &rsa.PSSOptions{SaltLength: opts.SaltLength, Hash: opts.hash()}
+}
+
+func hashReader(r io.Reader, hash crypto.Hash) ([]byte, error) {
+	if !hash.Available() {
+		return nil, errors.New("invalid hash")
+	}
+	h := hash.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
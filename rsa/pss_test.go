@@ -0,0 +1,93 @@
+package rsa
+
+import (
+	"bytes"
+	"crypto"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"log"
+	"strings"
+	"testing"
+)
+
+func ExampleSignPSS() {
+	priKey, pubKey, err := GenerateKeyPair(2048, PKCS8)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	src := []byte("trumanwong")
+	sign, err := SignPSS(src, priKey, crypto.SHA256, PSSSaltLengthEqualsHash)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = VerifyPSS(src, sign, pubKey, crypto.SHA256, PSSSaltLengthEqualsHash)
+	fmt.Println(err)
+	// Output: <nil>
+}
+
+func TestSignWithOptions(t *testing.T) {
+	priKey, pubKey, err := GenerateKeyPair(2048, PKCS1)
+	assert.NoError(t, err)
+
+	src := []byte("trumanwong")
+	tests := []*SignOptions{
+		nil,
+		{Scheme: PKCS1v15, Hash: crypto.SHA256},
+		{Scheme: PSS, Hash: crypto.SHA256},
+		{Scheme: PSS, Hash: crypto.SHA384, SaltLength: PSSSaltLengthEqualsHash},
+		{Scheme: PSS, Hash: crypto.SHA512, SaltLength: 20},
+		// zero Scheme and Hash default to PSS and SHA256
+		{},
+		{SaltLength: 32},
+		{Scheme: PKCS1v15},
+	}
+	for _, opts := range tests {
+		t.Run(fmt.Sprintf("%+v", opts), func(t *testing.T) {
+			sign, err := SignWithOptions(src, priKey, opts)
+			assert.NoError(t, err)
+			assert.NoError(t, VerifyWithOptions(src, sign, pubKey, opts))
+			assert.Error(t, VerifyWithOptions([]byte("TrumanWong"), sign, pubKey, opts))
+		})
+	}
+
+	t.Run("PKCS1v15MatchesSign", func(t *testing.T) {
+		sign, err := SignWithOptions(src, priKey, &SignOptions{Scheme: PKCS1v15, Hash: crypto.SHA256})
+		assert.NoError(t, err)
+		assert.NoError(t, Verify(src, sign, pubKey, crypto.SHA256))
+	})
+
+	t.Run("Defaults", func(t *testing.T) {
+		sign, err := SignWithOptions(src, priKey, &SignOptions{SaltLength: 32})
+		assert.NoError(t, err)
+		assert.NoError(t, VerifyWithOptions(src, sign, pubKey, nil))
+		assert.NoError(t, VerifyPSS(src, sign, pubKey, crypto.SHA256, 32))
+	})
+
+	t.Run("InvalidScheme", func(t *testing.T) {
+		_, err := SignWithOptions(src, priKey, &SignOptions{Scheme: OAEP})
+		assert.ErrorIs(t, err, ErrInvalidScheme)
+		assert.ErrorIs(t, VerifyWithOptions(src, make([]byte, 256), pubKey, &SignOptions{Scheme: OAEP}), ErrInvalidScheme)
+	})
+
+	t.Run("WrongSaltLength", func(t *testing.T) {
+		sign, err := SignPSS(src, priKey, crypto.SHA256, 16)
+		assert.NoError(t, err)
+		assert.Error(t, VerifyPSS(src, sign, pubKey, crypto.SHA256, 32))
+		assert.NoError(t, VerifyPSS(src, sign, pubKey, crypto.SHA256, PSSSaltLengthAuto))
+	})
+}
+
+func TestSignReader(t *testing.T) {
+	priKey, pubKey, err := GenerateKeyPair(2048, PKCS8)
+	assert.NoError(t, err)
+
+	src := strings.Repeat("trumanwong", 100000)
+	opts := &SignOptions{Scheme: PSS, Hash: crypto.SHA256}
+	sign, err := SignReader(strings.NewReader(src), priKey, opts)
+	assert.NoError(t, err)
+	assert.NoError(t, VerifyReader(strings.NewReader(src), sign, pubKey, opts))
+	assert.NoError(t, VerifyWithOptions([]byte(src), sign, pubKey, opts))
+	assert.Error(t, VerifyReader(bytes.NewReader(nil), sign, pubKey, opts))
+}
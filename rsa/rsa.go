@@ -7,7 +7,6 @@ import (
 	"crypto/x509"
 	"encoding/pem"
 	"errors"
-	"fmt"
 )
 
 type keyFormat string
@@ -24,42 +23,18 @@ func GenerateKeyPair(bits int, format keyFormat) (privateKey, publicKey []byte,
 	if err != nil {
 		return
 	}
-	var privateKeyBytes []byte
-	switch format {
-	case PKCS1:
-		privateKeyBytes = x509.MarshalPKCS1PrivateKey(key)
-		privateKey = pem.EncodeToMemory(&pem.Block{
-			Type:  "RSA PRIVATE KEY",
-			Bytes: privateKeyBytes,
-		})
-		publicKey = pem.EncodeToMemory(&pem.Block{
-			Type:  "RSA PUBLIC KEY",
-			Bytes: x509.MarshalPKCS1PublicKey(&key.PublicKey),
-		})
-	case PKCS8:
-		privateKeyBytes, err = x509.MarshalPKCS8PrivateKey(key)
-		if err != nil {
-			return nil, nil, errors.New(fmt.Sprintf("generate pkcs8 private key fail, %s", err))
-		}
-		privateKey = pem.EncodeToMemory(&pem.Block{
-			Type:  "PRIVATE KEY",
-			Bytes: privateKeyBytes,
-		})
-		publicKeyBytes, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
-		if err != nil {
-			return nil, nil, errors.New(fmt.Sprintf("generate public key fail, %s", err))
-		}
-		publicKey = pem.EncodeToMemory(&pem.Block{
-			Type:  "PUBLIC KEY",
-			Bytes: publicKeyBytes,
-		})
-	default:
-		return nil, nil, errors.New("invalid key format")
+	privateKey, err = marshalPrivateKey(key, format)
+	if err != nil {
+		return nil, nil, err
+	}
+	publicKey, err = marshalPublicKey(&key.PublicKey, format)
+	if err != nil {
+		return nil, nil, err
 	}
 	return
 }
 
-// parse public key from pem. support PKCS1 and PKCS8. return rsa.PublicKey
+// parse public key from pem. support PKCS1, PKCS8 and X.509 certificate. return rsa.PublicKey
 func parsePublicKey(publicKey []byte) (*rsa.PublicKey, error) {
 	block, _ := pem.Decode(publicKey)
 	if block == nil {
@@ -85,6 +60,11 @@ func parsePublicKey(publicKey []byte) (*rsa.PublicKey, error) {
 		if !ok {
 			return nil, errors.New("key is invalid public key")
 		}
+	case "CERTIFICATE":
+		rsaPublicKey, err = parseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
 	default:
 		return nil, errors.New("key is invalid format")
 	}
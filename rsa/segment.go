@@ -0,0 +1,170 @@
+package rsa
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// EncryptSegmented RSA Encrypt plaintext of any length by splitting it into
+// chunks that fit the modulus and concatenating the ciphertexts, nil opts
+// means OAEP with SHA256.
+func EncryptSegmented(src, publicKey []byte, opts *EncryptOptions) ([]byte, error) {
+	rsaPublicKey, err := parsePublicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	size, err := maxPlaintextSize(rsaPublicKey, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	for _, chunk := range split(src, size) {
+		dst, err := encrypt(rsaPublicKey, chunk, opts)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(dst)
+	}
+	return buf.Bytes(), nil
+}
+
+// DecryptSegmented RSA Decrypt ciphertext produced by EncryptSegmented.
+func DecryptSegmented(src, privateKey []byte, opts *EncryptOptions) ([]byte, error) {
+	rsaPrivateKey, err := parsePrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	k := rsaPrivateKey.Size()
+	if len(src) == 0 || len(src)%k != 0 {
+		return nil, errors.New("invalid ciphertext length")
+	}
+
+	var buf bytes.Buffer
+	for _, chunk := range split(src, k) {
+		dst, err := decrypt(rsaPrivateKey, chunk, opts)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(dst)
+	}
+	return buf.Bytes(), nil
+}
+
+// EncryptSegmentedBase64 EncryptSegmented and return the base64 encoded
+// ciphertext.
+func EncryptSegmentedBase64(src, publicKey []byte, opts *EncryptOptions) (string, error) {
+	dst, err := EncryptSegmented(src, publicKey, opts)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(dst), nil
+}
+
+// DecryptSegmentedBase64 DecryptSegmented a base64 encoded ciphertext.
+func DecryptSegmentedBase64(src string, privateKey []byte, opts *EncryptOptions) ([]byte, error) {
+	dst, err := base64.StdEncoding.DecodeString(src)
+	if err != nil {
+		return nil, err
+	}
+	return DecryptSegmented(dst, privateKey, opts)
+}
+
+// PrivateEncrypt RSA Encrypt with the private key using PKCS#1 v1.5 type 1
+// padding (RSA_private_encrypt in OpenSSL). Plaintext longer than the
+// modulus allows is split into chunks like EncryptSegmented.
+func PrivateEncrypt(src, privateKey []byte) ([]byte, error) {
+	rsaPrivateKey, err := parsePrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	for _, chunk := range split(src, rsaPrivateKey.Size()-11) {
+		// A zero hash makes SignPKCS1v15 pad and sign the input directly.
+		dst, err := rsa.SignPKCS1v15(rand.Reader, rsaPrivateKey, crypto.Hash(0), chunk)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(dst)
+	}
+	return buf.Bytes(), nil
+}
+
+// PublicDecrypt RSA Decrypt ciphertext produced by PrivateEncrypt with the
+// public key (RSA_public_decrypt in OpenSSL).
+func PublicDecrypt(src, publicKey []byte) ([]byte, error) {
+	rsaPublicKey, err := parsePublicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	k := rsaPublicKey.Size()
+	if len(src) == 0 || len(src)%k != 0 {
+		return nil, errors.New("invalid ciphertext length")
+	}
+
+	var buf bytes.Buffer
+	for _, chunk := range split(src, k) {
+		if new(big.Int).SetBytes(chunk).Cmp(rsaPublicKey.N) >= 0 {
+			return nil, rsa.ErrDecryption
+		}
+		em := encryptRaw(rsaPublicKey, chunk)
+		// EM = 0x00 || 0x01 || PS || 0x00 || M, PS is at least 8 bytes of 0xff.
+		if em[0] != 0 || em[1] != 1 {
+			return nil, rsa.ErrDecryption
+		}
+		i := 2
+		for i < len(em) && em[i] == 0xff {
+			i++
+		}
+		if i-2 < 8 || i == len(em) || em[i] != 0 {
+			return nil, rsa.ErrDecryption
+		}
+		buf.Write(em[i+1:])
+	}
+	return buf.Bytes(), nil
+}
+
+// maxPlaintextSize returns the longest plaintext a single RSA block can
+// carry with the scheme in opts.
+func maxPlaintextSize(pub *rsa.PublicKey, opts *EncryptOptions) (int, error) {
+	if opts == nil {
+		opts = defaultEncryptOptions
+	}
+	k := pub.Size()
+	switch opts.scheme() {
+	case PKCS1v15:
+		return k - 11, nil
+	case OAEP:
+		h, _ := opts.hashes()
+		hashFunc, err := h.New()
+		if err != nil {
+			return 0, err
+		}
+		if size := k - 2*hashFunc.Size() - 2; size > 0 {
+			return size, nil
+		}
+		return 0, rsa.ErrMessageTooLong
+	}
+	return 0, fmt.Errorf("%w: %q is not an encrypt scheme", ErrInvalidScheme, opts.Scheme)
+}
+
+// split splits src into chunks of at most size bytes. An empty src yields a
+// single empty chunk so that empty plaintext still round trips.
+func split(src []byte, size int) [][]byte {
+	if len(src) == 0 {
+		return [][]byte{src}
+	}
+	chunks := make([][]byte, 0, (len(src)+size-1)/size)
+	for len(src) > size {
+		chunks = append(chunks, src[:size])
+		src = src[size:]
+	}
+	return append(chunks, src)
+}
@@ -0,0 +1,95 @@
+package rsa
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"log"
+	"strings"
+	"testing"
+)
+
+func ExampleEncryptSegmentedBase64() {
+	priKey, pubKey, err := GenerateKeyPair(1024, PKCS8)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	src := []byte(strings.Repeat("trumanwong", 30))
+	dst, err := EncryptSegmentedBase64(src, pubKey, &EncryptOptions{Scheme: PKCS1v15})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	src, err = DecryptSegmentedBase64(dst, priKey, &EncryptOptions{Scheme: PKCS1v15})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(len(src))
+	// Output: 300
+}
+
+func TestEncryptSegmented(t *testing.T) {
+	priKey, pubKey, err := GenerateKeyPair(1024, PKCS1)
+	assert.NoError(t, err)
+
+	tests := []*EncryptOptions{
+		nil,
+		{Scheme: PKCS1v15},
+		{Scheme: OAEP, Hash: SHA1},
+		{Scheme: OAEP, Hash: SHA256, MGFHash: SHA1},
+		{Hash: SM3},
+	}
+	for _, opts := range tests {
+		for _, n := range []int{0, 1, 62, 117, 118, 1000} {
+			t.Run(fmt.Sprintf("%+v/%d", opts, n), func(t *testing.T) {
+				src := []byte(strings.Repeat("t", n))
+				dst, err := EncryptSegmented(src, pubKey, opts)
+				assert.NoError(t, err)
+				assert.Zero(t, len(dst)%128)
+
+				ret, err := DecryptSegmented(dst, priKey, opts)
+				assert.NoError(t, err)
+				assert.Equal(t, string(src), string(ret))
+			})
+		}
+	}
+
+	_, err = DecryptSegmented(make([]byte, 100), priKey, nil)
+	assert.Error(t, err)
+}
+
+func TestPrivateEncrypt(t *testing.T) {
+	priKey, pubKey, err := GenerateKeyPair(1024, PKCS8)
+	assert.NoError(t, err)
+
+	for _, n := range []int{0, 10, 117, 118, 500} {
+		t.Run(fmt.Sprint(n), func(t *testing.T) {
+			src := []byte(strings.Repeat("t", n))
+			dst, err := PrivateEncrypt(src, priKey)
+			assert.NoError(t, err)
+
+			ret, err := PublicDecrypt(dst, pubKey)
+			assert.NoError(t, err)
+			assert.Equal(t, string(src), string(ret))
+		})
+	}
+
+	t.Run("CompatibleWithRawSignature", func(t *testing.T) {
+		src := []byte("trumanwong")
+		dst, err := PrivateEncrypt(src, priKey)
+		assert.NoError(t, err)
+		rsaPublicKey, err := parsePublicKey(pubKey)
+		assert.NoError(t, err)
+		assert.NoError(t, rsa.VerifyPKCS1v15(rsaPublicKey, crypto.Hash(0), src, dst))
+	})
+
+	t.Run("Tampered", func(t *testing.T) {
+		dst, err := PrivateEncrypt([]byte("trumanwong"), priKey)
+		assert.NoError(t, err)
+		dst[10] ^= 1
+		_, err = PublicDecrypt(dst, pubKey)
+		assert.Error(t, err)
+	})
+}
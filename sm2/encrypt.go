@@ -0,0 +1,171 @@
+package sm2
+
+import (
+	"crypto/rand"
+	"encoding/asn1"
+	"errors"
+	"math/big"
+
+	"github.com/emmansun/gmsm/sm2"
+)
+
+type mode string
+
+const (
+	// C1C3C2 is the order of GB/T 32918.4-2016.
+	C1C3C2 mode = "c1c3c2"
+	// C1C2C3 is the order of the 2010 draft, still used by older systems.
+	C1C2C3 mode = "c1c2c3"
+)
+
+type encoding string
+
+const (
+	// Raw is the concatenation of C1 (an uncompressed point), C2 and C3.
+	Raw encoding = "raw"
+	// ASN1 is the SEQUENCE of x, y, C3 and C2 of GB/T 35276, or x, y, C2
+	// and C3 for C1C2C3.
+	ASN1 encoding = "asn1"
+)
+
+// EncryptOptions selects the ciphertext format of Encrypt and Decrypt. Nil
+// options mean Raw C1C3C2.
+type EncryptOptions struct {
+	Mode     mode
+	Encoding encoding
+}
+
+// asn1Ciphertext is the ASN.1 ciphertext with C2 before C3.
+type asn1Ciphertext struct {
+	X, Y *big.Int
+	C2   []byte
+	C3   []byte
+}
+
+// asn1CiphertextC3C2 is the ASN.1 ciphertext of GB/T 35276.
+type asn1CiphertextC3C2 struct {
+	X, Y *big.Int
+	C3   []byte
+	C2   []byte
+}
+
+// Encrypt sm2 encrypt with the public key pem.
+func Encrypt(src, publicKey []byte, opts *EncryptOptions) ([]byte, error) {
+	key, err := parsePublicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	mode, enc, err := opts.values()
+	if err != nil {
+		return nil, err
+	}
+	if enc == ASN1 {
+		dst, err := sm2.EncryptASN1(rand.Reader, key, src)
+		if err != nil || mode == C1C3C2 {
+			return dst, err
+		}
+		return swapASN1(dst, false)
+	}
+	order := sm2.C1C3C2
+	if mode == C1C2C3 {
+		order = sm2.C1C2C3
+	}
+	return sm2.Encrypt(rand.Reader, key, src, sm2.NewPlainEncrypterOpts(sm2.MarshalUncompressed, order))
+}
+
+// Decrypt sm2 decrypt with the private key pem.
+func Decrypt(src, privateKey []byte, opts *EncryptOptions) ([]byte, error) {
+	key, err := parsePrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	mode, enc, err := opts.values()
+	if err != nil {
+		return nil, err
+	}
+	if enc == ASN1 {
+		if mode == C1C2C3 {
+			if src, err = swapASN1(src, true); err != nil {
+				return nil, err
+			}
+		}
+		return key.Decrypt(nil, src, sm2.ASN1DecrypterOpts)
+	}
+	order := sm2.C1C3C2
+	if mode == C1C2C3 {
+		order = sm2.C1C2C3
+	}
+	return key.Decrypt(nil, src, sm2.NewPlainDecrypterOpts(order))
+}
+
+// ConvertCiphertext convert a ciphertext between the formats of from and
+// to without decrypting it.
+func ConvertCiphertext(src []byte, from, to *EncryptOptions) ([]byte, error) {
+	fromMode, fromEnc, err := from.values()
+	if err != nil {
+		return nil, err
+	}
+	toMode, toEnc, err := to.values()
+	if err != nil {
+		return nil, err
+	}
+
+	// normalize to ASN.1 C1C3C2
+	switch {
+	case fromEnc == ASN1 && fromMode == C1C2C3:
+		src, err = swapASN1(src, true)
+	case fromEnc == Raw && fromMode == C1C3C2:
+		src, err = sm2.PlainCiphertext2ASN1(src, sm2.C1C3C2)
+	case fromEnc == Raw && fromMode == C1C2C3:
+		src, err = sm2.PlainCiphertext2ASN1(src, sm2.C1C2C3)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case toEnc == ASN1 && toMode == C1C2C3:
+		return swapASN1(src, false)
+	case toEnc == Raw && toMode == C1C3C2:
+		return sm2.ASN1Ciphertext2Plain(src, sm2.NewPlainEncrypterOpts(sm2.MarshalUncompressed, sm2.C1C3C2))
+	case toEnc == Raw && toMode == C1C2C3:
+		return sm2.ASN1Ciphertext2Plain(src, sm2.NewPlainEncrypterOpts(sm2.MarshalUncompressed, sm2.C1C2C3))
+	}
+	return src, nil
+}
+
+func (opts *EncryptOptions) values() (mode, encoding, error) {
+	m, e := C1C3C2, Raw
+	if opts != nil {
+		if opts.Mode != "" {
+			m = opts.Mode
+		}
+		if opts.Encoding != "" {
+			e = opts.Encoding
+		}
+	}
+	if m != C1C3C2 && m != C1C2C3 {
+		return "", "", errors.New("unsupported sm2 ciphertext mode")
+	}
+	if e != Raw && e != ASN1 {
+		return "", "", errors.New("unsupported sm2 ciphertext encoding")
+	}
+	return m, e, nil
+}
+
+// swapASN1 converts an ASN.1 ciphertext from C1C3C2 to C1C2C3, or back if
+// toC3C2 is true.
+func swapASN1(src []byte, toC3C2 bool) ([]byte, error) {
+	if toC3C2 {
+		var c asn1Ciphertext
+		if rest, err := asn1.Unmarshal(src, &c); err != nil || len(rest) != 0 {
+			return nil, errors.New("invalid sm2 asn.1 ciphertext")
+		}
+		return asn1.Marshal(asn1CiphertextC3C2{c.X, c.Y, c.C3, c.C2})
+	}
+	var c asn1CiphertextC3C2
+	if rest, err := asn1.Unmarshal(src, &c); err != nil || len(rest) != 0 {
+		return nil, errors.New("invalid sm2 asn.1 ciphertext")
+	}
+	return asn1.Marshal(asn1Ciphertext{c.X, c.Y, c.C2, c.C3})
+}
@@ -0,0 +1,101 @@
+package sm2
+
+import (
+	"encoding/base64"
+	"fmt"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// openssl pkeyutl -encrypt -pubin -inkey pub.pem
+const opensslCiphertext = "MHQCIQCesj6L6WUKoe/n2bjijtHaeOwZk2yxp/v/aS7IpreG7AIhAOojYAUCbGfwtFMu6mz4molne6MV4E5swp7h21GY1/jEBCAmlXCu0yvhCQFRiWlusHH+XrEffidfrim1a2o99aCRSQQKv+8A8qUYIOAagA=="
+
+func ExampleEncrypt() {
+	priKey, pubKey, err := GenerateKeyPair(PKCS8)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	opts := &EncryptOptions{Mode: C1C3C2, Encoding: ASN1}
+	dst, err := Encrypt([]byte("trumanwong"), pubKey, opts)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	dst, err = Decrypt(dst, priKey, opts)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(string(dst))
+	// Output: trumanwong
+}
+
+func TestEncrypt(t *testing.T) {
+	priKey, pubKey, err := GenerateKeyPair(SEC1)
+	assert.NoError(t, err)
+
+	src := []byte("trumanwong")
+	tests := []*EncryptOptions{
+		nil,
+		{Mode: C1C3C2, Encoding: Raw},
+		{Mode: C1C2C3, Encoding: Raw},
+		{Mode: C1C3C2, Encoding: ASN1},
+		{Mode: C1C2C3, Encoding: ASN1},
+		{Encoding: ASN1},
+	}
+	for _, opts := range tests {
+		t.Run(fmt.Sprintf("%+v", opts), func(t *testing.T) {
+			dst, err := Encrypt(src, pubKey, opts)
+			assert.NoError(t, err)
+			if opts == nil || opts.Encoding != ASN1 {
+				// 0x04 || x || y || C2 || C3
+				assert.Len(t, dst, 1+64+len(src)+32)
+				assert.Equal(t, byte(4), dst[0])
+			}
+
+			plain, err := Decrypt(dst, priKey, opts)
+			assert.NoError(t, err)
+			assert.Equal(t, src, plain)
+
+			for _, to := range tests {
+				converted, err := ConvertCiphertext(dst, opts, to)
+				assert.NoError(t, err)
+				plain, err := Decrypt(converted, priKey, to)
+				assert.NoError(t, err)
+				assert.Equal(t, src, plain)
+			}
+		})
+	}
+
+	// the order matters
+	dst, err := Encrypt(src, pubKey, &EncryptOptions{Mode: C1C2C3})
+	assert.NoError(t, err)
+	_, err = Decrypt(dst, priKey, &EncryptOptions{Mode: C1C3C2})
+	assert.Error(t, err)
+	dst, err = Encrypt(src, pubKey, &EncryptOptions{Mode: C1C2C3, Encoding: ASN1})
+	assert.NoError(t, err)
+	_, err = Decrypt(dst, priKey, &EncryptOptions{Mode: C1C3C2, Encoding: ASN1})
+	assert.Error(t, err)
+
+	_, err = Encrypt(src, pubKey, &EncryptOptions{Mode: "c2c1c3"})
+	assert.Error(t, err)
+	_, err = Encrypt(src, pubKey, &EncryptOptions{Encoding: "hex"})
+	assert.Error(t, err)
+	_, err = Decrypt([]byte("trumanwong"), priKey, &EncryptOptions{Encoding: ASN1})
+	assert.Error(t, err)
+}
+
+func TestDecryptOpenSSL(t *testing.T) {
+	src, _ := base64.StdEncoding.DecodeString(opensslCiphertext)
+	dst, err := Decrypt(src, []byte(opensslPrivateKey), &EncryptOptions{Encoding: ASN1})
+	assert.NoError(t, err)
+	assert.Equal(t, "trumanwong", string(dst))
+
+	raw, err := ConvertCiphertext(src, &EncryptOptions{Encoding: ASN1}, &EncryptOptions{Mode: C1C2C3})
+	assert.NoError(t, err)
+	dst, err = Decrypt(raw, []byte(opensslPrivateKey), &EncryptOptions{Mode: C1C2C3})
+	assert.NoError(t, err)
+	assert.Equal(t, "trumanwong", string(dst))
+}
@@ -0,0 +1,104 @@
+package sm2
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+
+	"github.com/emmansun/gmsm/sm2"
+)
+
+// KeyExchange is one side of the sm2 key exchange protocol of GB/T 32918.3.
+// Ephemeral public keys are exchanged as uncompressed points.
+//
+// The initiator calls Init, sends RA to the responder, and calls
+// ConfirmResponder with the responder's RB and SB, which returns the key
+// and SA. The responder calls Respond with RA, which returns RB and SB, and
+// calls ConfirmInitiator with SA, which returns the key.
+type KeyExchange struct {
+	ke *sm2.KeyExchange
+}
+
+// NewKeyExchange create one side of a key exchange with the own private key
+// pem, the peer's public key pem and the user IDs of both sides (DefaultUID
+// if nil). keyLen is the length of the agreed key in bytes. If confirm is
+// true, the optional key confirmation signatures SB and SA are generated.
+func NewKeyExchange(privateKey, peerPublicKey, uid, peerUID []byte, keyLen int, confirm bool) (*KeyExchange, error) {
+	key, err := parsePrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	peer, err := parsePublicKey(peerPublicKey)
+	if err != nil {
+		return nil, err
+	}
+	if uid == nil {
+		uid = DefaultUID
+	}
+	if peerUID == nil {
+		peerUID = DefaultUID
+	}
+	ke, err := sm2.NewKeyExchange(key, peer, uid, peerUID, keyLen, confirm)
+	if err != nil {
+		return nil, err
+	}
+	return &KeyExchange{ke: ke}, nil
+}
+
+// Init is the initiator's first step, return the ephemeral public key RA.
+func (k *KeyExchange) Init() ([]byte, error) {
+	ra, err := k.ke.InitKeyExchange(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return marshalPoint(ra), nil
+}
+
+// Respond is the responder's step, return the ephemeral public key RB and
+// the confirmation signature SB, which is nil unless confirm is set.
+func (k *KeyExchange) Respond(ra []byte) (rb, sb []byte, err error) {
+	pub, err := unmarshalPoint(ra)
+	if err != nil {
+		return nil, nil, err
+	}
+	rbKey, sb, err := k.ke.RepondKeyExchange(rand.Reader, pub)
+	if err != nil {
+		return nil, nil, err
+	}
+	return marshalPoint(rbKey), sb, nil
+}
+
+// ConfirmResponder is the initiator's second step. It verifies sb if given
+// and return the agreed key and the confirmation signature SA, which is nil
+// unless confirm is set.
+func (k *KeyExchange) ConfirmResponder(rb, sb []byte) (key, sa []byte, err error) {
+	pub, err := unmarshalPoint(rb)
+	if err != nil {
+		return nil, nil, err
+	}
+	return k.ke.ConfirmResponder(pub, sb)
+}
+
+// ConfirmInitiator is the responder's last step. It verifies sa if given
+// and return the agreed key.
+func (k *KeyExchange) ConfirmInitiator(sa []byte) ([]byte, error) {
+	return k.ke.ConfirmInitiator(sa)
+}
+
+// Destroy clears the ephemeral secrets of the key exchange.
+func (k *KeyExchange) Destroy() {
+	k.ke.Destroy()
+}
+
+func marshalPoint(pub *ecdsa.PublicKey) []byte {
+	return elliptic.Marshal(pub.Curve, pub.X, pub.Y)
+}
+
+func unmarshalPoint(b []byte) (*ecdsa.PublicKey, error) {
+	pub, err := sm2.NewPublicKey(b)
+	if err != nil {
+		return nil, errors.New("invalid sm2 ephemeral public key")
+	}
+	return pub, nil
+}
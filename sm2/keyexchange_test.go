@@ -0,0 +1,105 @@
+package sm2
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func ExampleKeyExchange() {
+	priKeyA, pubKeyA, err := GenerateKeyPair(PKCS8)
+	if err != nil {
+		log.Fatal(err)
+	}
+	priKeyB, pubKeyB, err := GenerateKeyPair(PKCS8)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	initiator, err := NewKeyExchange(priKeyA, pubKeyB, []byte("alice"), []byte("bob"), 16, true)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer initiator.Destroy()
+	responder, err := NewKeyExchange(priKeyB, pubKeyA, []byte("bob"), []byte("alice"), 16, true)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer responder.Destroy()
+
+	ra, err := initiator.Init()
+	if err != nil {
+		log.Fatal(err)
+	}
+	rb, sb, err := responder.Respond(ra)
+	if err != nil {
+		log.Fatal(err)
+	}
+	keyA, sa, err := initiator.ConfirmResponder(rb, sb)
+	if err != nil {
+		log.Fatal(err)
+	}
+	keyB, err := responder.ConfirmInitiator(sa)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(len(keyA), bytes.Equal(keyA, keyB))
+	// Output: 16 true
+}
+
+func TestKeyExchange(t *testing.T) {
+	priKeyA, pubKeyA, err := GenerateKeyPair(SEC1)
+	assert.NoError(t, err)
+	priKeyB, pubKeyB, err := GenerateKeyPair(PKCS8)
+	assert.NoError(t, err)
+
+	t.Run("WithoutConfirmation", func(t *testing.T) {
+		initiator, err := NewKeyExchange(priKeyA, pubKeyB, nil, nil, 48, false)
+		assert.NoError(t, err)
+		responder, err := NewKeyExchange(priKeyB, pubKeyA, nil, nil, 48, false)
+		assert.NoError(t, err)
+
+		ra, err := initiator.Init()
+		assert.NoError(t, err)
+		assert.Len(t, ra, 65)
+		rb, sb, err := responder.Respond(ra)
+		assert.NoError(t, err)
+		assert.Nil(t, sb)
+		keyA, sa, err := initiator.ConfirmResponder(rb, sb)
+		assert.NoError(t, err)
+		assert.Nil(t, sa)
+		keyB, err := responder.ConfirmInitiator(sa)
+		assert.NoError(t, err)
+		assert.Len(t, keyA, 48)
+		assert.Equal(t, keyA, keyB)
+	})
+
+	t.Run("UIDMismatch", func(t *testing.T) {
+		initiator, err := NewKeyExchange(priKeyA, pubKeyB, []byte("alice"), nil, 16, true)
+		assert.NoError(t, err)
+		responder, err := NewKeyExchange(priKeyB, pubKeyA, nil, nil, 16, true)
+		assert.NoError(t, err)
+
+		ra, err := initiator.Init()
+		assert.NoError(t, err)
+		rb, sb, err := responder.Respond(ra)
+		assert.NoError(t, err)
+		_, _, err = initiator.ConfirmResponder(rb, sb)
+		assert.Error(t, err)
+	})
+
+	t.Run("InvalidPoint", func(t *testing.T) {
+		responder, err := NewKeyExchange(priKeyB, pubKeyA, nil, nil, 16, true)
+		assert.NoError(t, err)
+		ra := make([]byte, 65)
+		ra[0] = 4
+		_, _, err = responder.Respond(ra)
+		assert.Error(t, err)
+	})
+
+	_, err = NewKeyExchange(pubKeyA, pubKeyB, nil, nil, 16, true)
+	assert.Error(t, err)
+}
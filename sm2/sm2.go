@@ -0,0 +1,180 @@
+package sm2
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"encoding/pem"
+	"errors"
+
+	"github.com/emmansun/gmsm/sm2"
+	"github.com/emmansun/gmsm/smx509"
+)
+
+type keyFormat string
+
+const (
+	SEC1  keyFormat = "sec1"
+	PKCS8 keyFormat = "pkcs8"
+)
+
+// DefaultUID is the user ID of GB/T 35276 used when no user ID is given.
+var DefaultUID = []byte("1234567812345678")
+
+// GenerateKeyPair Generate sm2 key pair with format(SEC1 or PKCS8), return
+// private key and PKIX public key.
+func GenerateKeyPair(format keyFormat) (privateKey, publicKey []byte, err error) {
+	key, err := sm2.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	privateKey, err = marshalPrivateKey(key, format)
+	if err != nil {
+		return nil, nil, err
+	}
+	publicKey, err = marshalPublicKey(&key.PublicKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return privateKey, publicKey, nil
+}
+
+// ConvertPrivateKey convert the private key pem to format(SEC1 or PKCS8).
+func ConvertPrivateKey(privateKey []byte, format keyFormat) ([]byte, error) {
+	key, err := parsePrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	return marshalPrivateKey(key, format)
+}
+
+// ExtractPublicKey return the PKIX public key pem of the private key.
+func ExtractPublicKey(privateKey []byte) ([]byte, error) {
+	key, err := parsePrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	return marshalPublicKey(&key.PublicKey)
+}
+
+// Sign sm2 sign with uid, return ASN.1 DER signature. The digest is
+// SM3(ZA || src), if uid is nil DefaultUID is used.
+func Sign(src, privateKey, uid []byte) ([]byte, error) {
+	key, err := parsePrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	if uid == nil {
+		uid = DefaultUID
+	}
+	return key.SignWithSM2(rand.Reader, uid, src)
+}
+
+// Verify sm2 verify an ASN.1 DER signature with uid. If uid is nil
+// DefaultUID is used.
+func Verify(src, sign, publicKey, uid []byte) error {
+	key, err := parsePublicKey(publicKey)
+	if err != nil {
+		return err
+	}
+	if uid == nil {
+		uid = DefaultUID
+	}
+	if !sm2.VerifyASN1WithSM2(key, uid, src, sign) {
+		return sm2.ErrInvalidSignature
+	}
+	return nil
+}
+
+// ZA return the user identity hash ZA of GB/T 32918.2 of the public key and
+// uid. If uid is nil DefaultUID is used.
+func ZA(publicKey, uid []byte) ([]byte, error) {
+	key, err := parsePublicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	if uid == nil {
+		uid = DefaultUID
+	}
+	return sm2.CalculateZA(key, uid)
+}
+
+func marshalPrivateKey(key *sm2.PrivateKey, format keyFormat) ([]byte, error) {
+	var block *pem.Block
+	switch format {
+	case SEC1:
+		der, err := smx509.MarshalSM2PrivateKey(key)
+		if err != nil {
+			return nil, err
+		}
+		block = &pem.Block{Type: "EC PRIVATE KEY", Bytes: der}
+	case PKCS8:
+		der, err := smx509.MarshalPKCS8PrivateKey(key)
+		if err != nil {
+			return nil, err
+		}
+		block = &pem.Block{Type: "PRIVATE KEY", Bytes: der}
+	default:
+		return nil, errors.New("invalid key format")
+	}
+	return pem.EncodeToMemory(block), nil
+}
+
+func marshalPublicKey(key *ecdsa.PublicKey) ([]byte, error) {
+	der, err := smx509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), nil
+}
+
+// parse private key from pem. support SEC1 and PKCS8. return sm2.PrivateKey
+func parsePrivateKey(privateKey []byte) (*sm2.PrivateKey, error) {
+	block, _ := pem.Decode(privateKey)
+	if block == nil {
+		return nil, errors.New("key is invalid format")
+	}
+	switch block.Type {
+	case "EC PRIVATE KEY":
+		return smx509.ParseSM2PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err := smx509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		sm2Key, ok := key.(*sm2.PrivateKey)
+		if !ok {
+			return nil, errors.New("key is invalid private key")
+		}
+		return sm2Key, nil
+	}
+	return nil, errors.New("key is invalid format")
+}
+
+// parse public key from pem. support PKIX and X.509 certificate. return
+// ecdsa.PublicKey on the sm2 curve
+func parsePublicKey(publicKey []byte) (*ecdsa.PublicKey, error) {
+	block, _ := pem.Decode(publicKey)
+	if block == nil {
+		return nil, errors.New("key is invalid format")
+	}
+	var key any
+	switch block.Type {
+	case "PUBLIC KEY":
+		var err error
+		if key, err = smx509.ParsePKIXPublicKey(block.Bytes); err != nil {
+			return nil, err
+		}
+	case "CERTIFICATE":
+		cert, err := smx509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		key = cert.PublicKey
+	default:
+		return nil, errors.New("key is invalid format")
+	}
+	if !sm2.IsSM2PublicKey(key) {
+		return nil, errors.New("key is invalid public key")
+	}
+	return key.(*ecdsa.PublicKey), nil
+}
@@ -0,0 +1,94 @@
+package cryptogo
+
+import "io"
+
+// wrapLines breaks src with CRLF every n bytes, as MIME does with n = 76.
+func wrapLines(src []byte, n int) []byte {
+	if n <= 0 || len(src) <= n {
+		return src
+	}
+	dst := make([]byte, 0, len(src)+(len(src)-1)/n*2)
+	for len(src) > n {
+		dst = append(append(dst, src[:n]...), '\r', '\n')
+		src = src[n:]
+	}
+	return append(dst, src...)
+}
+
+// lineWriter is the streaming wrapLines.
+type lineWriter struct {
+	w   io.Writer
+	n   int
+	col int
+}
+
+func (l *lineWriter) Write(p []byte) (written int, err error) {
+	for len(p) > 0 {
+		if l.col == l.n {
+			if _, err = io.WriteString(l.w, "\r\n"); err != nil {
+				return written, err
+			}
+			l.col = 0
+		}
+		m, err := l.w.Write(p[:min(l.n-l.col, len(p))])
+		written += m
+		l.col += m
+		if err != nil {
+			return written, err
+		}
+		p = p[m:]
+	}
+	return written, nil
+}
+
+// mapBytes returns a copy of src with each byte replaced by mapping, or
+// dropped if mapping returns false.
+func mapBytes(src []byte, mapping func(c byte) (byte, bool)) []byte {
+	dst := make([]byte, 0, len(src))
+	for _, c := range src {
+		if c, ok := mapping(c); ok {
+			dst = append(dst, c)
+		}
+	}
+	return dst
+}
+
+// mapReader is the streaming mapBytes.
+type mapReader struct {
+	r       io.Reader
+	mapping func(c byte) (byte, bool)
+}
+
+func (m *mapReader) Read(p []byte) (int, error) {
+	for {
+		n, err := m.r.Read(p)
+		kept := 0
+		for _, c := range p[:n] {
+			if c, ok := m.mapping(c); ok {
+				p[kept] = c
+				kept++
+			}
+		}
+		if kept > 0 || err != nil {
+			return kept, err
+		}
+	}
+}
+
+func dropSpace(c byte) (byte, bool) {
+	switch c {
+	case ' ', '\t', '\r', '\n', '\v', '\f':
+		return c, false
+	}
+	return c, true
+}
+
+// errStream is a reader and writer failing with err, for streams whose
+// options are invalid.
+type errStream struct {
+	err error
+}
+
+func (e errStream) Read([]byte) (int, error)  { return 0, e.err }
+func (e errStream) Write([]byte) (int, error) { return 0, e.err }
+func (e errStream) Close() error              { return e.err }
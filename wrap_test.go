@@ -0,0 +1,34 @@
+package cryptogo
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestWrapLines(t *testing.T) {
+	assert.Equal(t, "abc", string(wrapLines([]byte("abc"), 0)))
+	assert.Equal(t, "abc", string(wrapLines([]byte("abc"), 3)))
+	assert.Equal(t, "ab\r\nc", string(wrapLines([]byte("abc"), 2)))
+	assert.Equal(t, "a\r\nb\r\nc", string(wrapLines([]byte("abc"), 1)))
+
+	var buf bytes.Buffer
+	w := &lineWriter{w: &buf, n: 2}
+	for _, p := range []string{"a", "bcd", "", "efgh"} {
+		n, err := w.Write([]byte(p))
+		assert.NoError(t, err)
+		assert.Equal(t, len(p), n)
+	}
+	assert.Equal(t, "ab\r\ncd\r\nef\r\ngh", buf.String())
+}
+
+func TestMapReader(t *testing.T) {
+	assert.Equal(t, []byte("abc"), mapBytes([]byte(" a\tb\nc "), dropSpace))
+
+	ret, err := io.ReadAll(&mapReader{r: iotest.OneByteReader(strings.NewReader("  a \t b\n c  ")), mapping: dropSpace})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("abc"), ret)
+}
@@ -0,0 +1,28 @@
+package cryptogo
+
+import "io"
+
+var z85 = newBase85Encoding("z85", "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ.-:+=^!/*?&<>()[]{}@%$#")
+
+// Z85Encode z85 encode, the ZeroMQ encoding of CURVE keys. The ZeroMQ
+// specification only defines inputs whose length is a multiple of 4, other
+// lengths are encoded with a shortened last group like Base85Encode.
+func Z85Encode(src []byte) []byte {
+	return z85.Encode(src)
+}
+
+// Z85Decode z85 decode
+func Z85Decode(src []byte) ([]byte, error) {
+	return z85.Decode(src)
+}
+
+// NewZ85Encoder returns a streaming Z85Encode writing to w. Close must be
+// called to flush the last partial group.
+func NewZ85Encoder(w io.Writer) io.WriteCloser {
+	return z85.NewEncoder(w)
+}
+
+// NewZ85Decoder returns a streaming Z85Decode reading from r.
+func NewZ85Decoder(r io.Reader) io.Reader {
+	return z85.NewDecoder(r)
+}
@@ -0,0 +1,72 @@
+package cryptogo
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"testing"
+)
+
+func ExampleZ85Encode() {
+	encode := Z85Encode([]byte{0x86, 0x4f, 0xd2, 0x6f, 0xb5, 0x59, 0xf7, 0x5b})
+	fmt.Println(string(encode))
+	// Output: HelloWorld
+}
+
+func TestZ85Encode(t *testing.T) {
+	tests := []struct {
+		src      string
+		expected string
+	}{
+		{"", ""},
+		{"8e0bdd697628b91d8f245559f2d40ecdbd95cb01e75e71d1f99d2f4ae14f2e3e", "JTKVSB%%)wK0E.d]3!e]Y{l+w>u-eQ}jffh&zh6&"},
+		{"0000000061", "00000ve"},
+		{hex.EncodeToString([]byte("TrumanWong")), "rc0a#vqYd+zF5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			src, err := hex.DecodeString(tt.src)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, string(Z85Encode(src)))
+		})
+	}
+}
+
+func ExampleZ85Decode() {
+	decode, err := Z85Decode([]byte("HelloWorld"))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Printf("%x\n", decode)
+	// Output: 864fd26fb559f75b
+}
+
+func TestZ85Decode(t *testing.T) {
+	t.Run("TestZ85Decode", func(t *testing.T) {
+		ret, err := Z85Decode([]byte("rc0a#vqYd+zF5"))
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("TrumanWong"), ret)
+
+		for _, src := range []string{"H", "HelloWorl_", "HelloW", "%%%%%"} {
+			_, err = Z85Decode([]byte(src))
+			assert.Error(t, err, src)
+		}
+	})
+}
+
+func TestZ85Stream(t *testing.T) {
+	src := bytes.Repeat([]byte{0x86, 0x4f, 0xd2, 0x6f, 0xb5, 0x59, 0xf7, 0x5b}, 300)
+	var buf bytes.Buffer
+	w := NewZ85Encoder(&buf)
+	_, err := w.Write(src)
+	assert.NoError(t, err)
+	assert.NoError(t, w.Close())
+	assert.Equal(t, bytes.Repeat([]byte("HelloWorld"), 300), buf.Bytes())
+
+	ret, err := io.ReadAll(NewZ85Decoder(&buf))
+	assert.NoError(t, err)
+	assert.Equal(t, src, ret)
+}